@@ -0,0 +1,355 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/credentials"
+	"jira-tools/internal/jira"
+)
+
+// Authentication methods, set with JIRA_AUTH.
+const (
+	authBasic = "basic"
+	authPAT   = "pat"
+	authOAuth = "oauth2"
+)
+
+const defaultOAuthRedirectURL = "http://localhost:8085/callback"
+
+// envSetting is a line of the .env file of a profile.
+type envSetting struct {
+	Key   string
+	Value string
+}
+
+type envSettings []envSetting
+
+// Get returns the value of key, or "" when it is not set.
+func (s envSettings) Get(key string) string {
+	for _, setting := range s {
+		if setting.Key == key {
+			return setting.Value
+		}
+	}
+	return ""
+}
+
+// String formats the settings as .env lines, leaving out empty values.
+func (s envSettings) String() string {
+	var b strings.Builder
+	for _, setting := range s {
+		if setting.Value != "" {
+			fmt.Fprintf(&b, "%s=%s\n", setting.Key, setting.Value)
+		}
+	}
+	return b.String()
+}
+
+// jiraOptions builds the client options from the connection settings,
+// looked up with getenv.
+func jiraOptions(getenv func(string) string) (jira.Options, error) {
+	domain := getenv("JIRA_DOMAIN")
+	if domain == "" {
+		if activeProfile != config.DefaultProfile {
+			return jira.Options{}, fmt.Errorf("profile %q is not set up, run 'jt %ssetup' first", activeProfile, profileFlag())
+		}
+		return jira.Options{}, fmt.Errorf("JIRA_DOMAIN is not set, run 'jt setup' first")
+	}
+
+	opts := jira.Options{
+		BaseURL:    domain,
+		APIVersion: getenv("JIRA_API_VERSION"),
+		CAFile:     getenv("JIRA_CA_BUNDLE"),
+		CertFile:   getenv("JIRA_CLIENT_CERT"),
+		KeyFile:    getenv("JIRA_CLIENT_KEY"),
+	}
+
+	switch method := getenv("JIRA_AUTH"); method {
+	case "", authBasic:
+		opts.Auth = jira.BasicAuth{Email: getenv("JIRA_EMAIL"), APIToken: getenv("JIRA_API_TOKEN")}
+	case authPAT:
+		if getenv("JIRA_API_TOKEN") == "" {
+			return opts, fmt.Errorf("JIRA_API_TOKEN is not set, run 'jt %sauth login' to save the personal access token", profileFlag())
+		}
+		opts.Auth = jira.BearerAuth{Token: getenv("JIRA_API_TOKEN")}
+	case authOAuth:
+		token, err := decodeOAuthToken(getenv(oauthTokenKey))
+		if err != nil {
+			return opts, err
+		}
+		if token == nil {
+			return opts, fmt.Errorf("not logged in with OAuth 2.0, run 'jt %sauth login' first", profileFlag())
+		}
+		opts.APIURL = jira.OAuthAPIURL(token.CloudID)
+		opts.Auth = &jira.OAuth2Auth{Config: oauthConfig(getenv), Token: token, OnRefresh: saveRefreshedOAuthToken}
+	default:
+		return opts, fmt.Errorf("unsupported JIRA_AUTH %q, expected %s, %s or %s", method, authBasic, authPAT, authOAuth)
+	}
+	return opts, nil
+}
+
+func oauthConfig(getenv func(string) string) jira.OAuthConfig {
+	redirectURL := getenv("JIRA_OAUTH_REDIRECT_URL")
+	if redirectURL == "" {
+		redirectURL = defaultOAuthRedirectURL
+	}
+	return jira.OAuthConfig{
+		ClientID:     getenv("JIRA_OAUTH_CLIENT_ID"),
+		ClientSecret: getenv("JIRA_OAUTH_CLIENT_SECRET"),
+		RedirectURL:  redirectURL,
+	}
+}
+
+// promptJiraSettings asks for the Jira site and how to authenticate to it.
+func promptJiraSettings() (envSettings, error) {
+	domain := promptUser("Jira URL (e.g., company.atlassian.net or https://jira.example.com/jira): ")
+	settings := envSettings{{"JIRA_DOMAIN", domain}}
+
+	fmt.Println("\nAuthentication method:")
+	selfHosted := false
+	method, err := chooseOption([]string{
+		"Email and API token (Jira Cloud)",
+		"Personal access token (Jira Server / Data Center)",
+		"Username and password (Jira Server / Data Center)",
+		"OAuth 2.0 app (Jira Cloud)",
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch method {
+	case 0:
+		settings = append(settings,
+			envSetting{"JIRA_EMAIL", promptUser("Jira Email: ")},
+			envSetting{"JIRA_API_TOKEN", promptSecret("Jira API Token: ")})
+	case 1:
+		settings = append(settings,
+			envSetting{"JIRA_AUTH", authPAT},
+			envSetting{"JIRA_API_TOKEN", promptSecret("Personal Access Token: ")})
+		selfHosted = true
+	case 2:
+		settings = append(settings,
+			envSetting{"JIRA_AUTH", authBasic},
+			envSetting{"JIRA_EMAIL", promptUser("Username: ")},
+			envSetting{"JIRA_API_TOKEN", promptSecret("Password: ")})
+		selfHosted = true
+	case 3:
+		redirectURL := promptUser(fmt.Sprintf("Callback URL registered for the app [%s]: ", defaultOAuthRedirectURL))
+		if redirectURL == defaultOAuthRedirectURL {
+			redirectURL = ""
+		}
+		settings = append(settings,
+			envSetting{"JIRA_AUTH", authOAuth},
+			envSetting{"JIRA_OAUTH_CLIENT_ID", promptUser("OAuth Client ID: ")},
+			envSetting{"JIRA_OAUTH_CLIENT_SECRET", promptSecret("OAuth Client Secret: ")},
+			envSetting{"JIRA_OAUTH_REDIRECT_URL", redirectURL})
+	}
+
+	if selfHosted {
+		settings = append(settings, envSetting{"JIRA_CA_BUNDLE", promptUser("CA bundle file (leave empty to trust the system CAs): ")})
+		if cert := promptUser("Client certificate file (leave empty if not required): "); cert != "" {
+			settings = append(settings,
+				envSetting{"JIRA_CLIENT_CERT", cert},
+				envSetting{"JIRA_CLIENT_KEY", promptUser("Client key file: ")})
+		}
+	}
+	return settings, nil
+}
+
+// setupJira asks for the Jira settings, logs in when using OAuth 2.0,
+// validates the credentials and saves the secrets in the credential store
+// the user picks. It returns the settings for the .env file.
+func setupJira() (envSettings, error) {
+	settings, err := promptJiraSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.Get("JIRA_AUTH") == authOAuth {
+		token, err := oauthLogin(oauthConfig(settings.Get), settings.Get("JIRA_DOMAIN"))
+		if err != nil {
+			return nil, fmt.Errorf("OAuth login failed: %v", err)
+		}
+		encoded, err := encodeOAuthToken(token)
+		if err != nil {
+			return nil, err
+		}
+		// Stored with the other secrets once the store is chosen
+		settings = append(settings, envSetting{oauthTokenKey, encoded})
+	}
+
+	fmt.Println("\nValidating Jira credentials...")
+	if err := validateCredentials(settings); err != nil {
+		return nil, fmt.Errorf("credential validation failed: %v", err)
+	}
+	kind, err := chooseCredentialStore()
+	if err != nil {
+		return nil, err
+	}
+	return storeSecrets(kind, settings)
+}
+
+// authMethodName describes a JIRA_AUTH value.
+func authMethodName(method string) string {
+	switch method {
+	case authPAT:
+		return "personal access token"
+	case authOAuth:
+		return "OAuth 2.0"
+	default:
+		return "basic (email or username with API token or password)"
+	}
+}
+
+// encodeOAuthToken serializes a token for the credential store.
+func encodeOAuthToken(token *jira.OAuthToken) (string, error) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// decodeOAuthToken parses a token read from the credential store, or
+// returns nil when there is none.
+func decodeOAuthToken(value string) (*jira.OAuthToken, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var token jira.OAuthToken
+	if err := json.Unmarshal([]byte(value), &token); err != nil {
+		return nil, fmt.Errorf("invalid %s, run 'jt %sauth login' again: %v", oauthTokenKey, profileFlag(), err)
+	}
+	return &token, nil
+}
+
+// saveRefreshedOAuthToken replaces the OAuth token in the credential store
+// after a refresh. Failing to save only warns: the refreshed token keeps
+// working for this command, the next one has to log in again.
+func saveRefreshedOAuthToken(token *jira.OAuthToken) {
+	encoded, err := encodeOAuthToken(token)
+	if err == nil {
+		os.Setenv(oauthTokenKey, encoded)
+		var store credentials.Store
+		if store, err = newCredentialStore(os.Getenv(credentialStoreEnv)); err == nil {
+			err = store.Set(oauthTokenKey, encoded)
+		}
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to save the refreshed OAuth token, run 'jt %sauth login' if the next command is not logged in: %v\n", profileFlag(), err)
+	}
+}
+
+// oauthLogin runs the OAuth 2.0 authorization code flow: the user grants
+// access in the browser, which is redirected to a local server with the
+// code. It returns a token for the given site.
+func oauthLogin(cfg jira.OAuthConfig, site string) (*jira.OAuthToken, error) {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("the OAuth client ID and secret are required")
+	}
+	redirect, err := url.Parse(cfg.RedirectURL)
+	if err != nil || redirect.Host == "" {
+		return nil, fmt.Errorf("invalid OAuth callback URL %q", cfg.RedirectURL)
+	}
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("cannot listen on %s for the OAuth callback: %v", redirect.Host, err)
+	}
+
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		return nil, err
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	type callback struct {
+		code string
+		err  error
+	}
+	callbacks := make(chan callback, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != redirect.Path {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		result := callback{code: query.Get("code")}
+		switch {
+		case query.Get("state") != state:
+			result.err = fmt.Errorf("the OAuth callback has an unexpected state")
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization failed: %s %s", query.Get("error"), query.Get("error_description"))
+		}
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "jt is authorized, you can close this window.")
+		}
+		select {
+		case callbacks <- result:
+		default:
+		}
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authURL := cfg.AuthCodeURL(state)
+	fmt.Printf("\nOpen this URL to authorize jt:\n%s\n\nWaiting for authorization...\n", authURL)
+	openBrowser(authURL)
+
+	var result callback
+	select {
+	case result = <-callbacks:
+	case <-time.After(5 * time.Minute):
+		return nil, fmt.Errorf("timed out waiting for authorization")
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, err := cfg.Exchange(result.code)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := cfg.AccessibleResources(token)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, resource := range resources {
+		if jira.NormalizeBaseURL(resource.URL) == jira.NormalizeBaseURL(site) {
+			token.CloudID = resource.ID
+			return token, nil
+		}
+		names = append(names, resource.URL)
+	}
+	return nil, fmt.Errorf("the app was not granted access to %s (granted: %s)", site, strings.Join(names, ", "))
+}
+
+// openBrowser tries to open url in the default browser. The URL is printed
+// too, so failures are ignored.
+func openBrowser(url string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if cmd.Start() == nil {
+		go cmd.Wait()
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"jira-tools/internal/changelog"
+	"jira-tools/internal/git"
+	"jira-tools/internal/jira"
+)
+
+func handleChangelog(args []string) error {
+	fs := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	group := fs.String("group", changelog.GroupByType, "group by type, issuetype or epic")
+	format := fs.String("format", "markdown", "output format: markdown, text or json")
+	output := fs.String("output", "", "write to a file instead of stdout")
+	update := fs.Bool("update", false, "update CHANGELOG.md in Keep a Changelog format")
+	file := fs.String("file", "CHANGELOG.md", "changelog file for --update, relative to the project root")
+	version := fs.String("version", "", "version name (default: the tag at the end of the range, or Unreleased)")
+	date := fs.String("date", "", "release date (YYYY-MM-DD)")
+	noJira := fs.Bool("no-jira", false, "do not fetch issue details from Jira")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("usage: jt changelog [from..to] [--group type|issuetype|epic] [--format markdown|text|json] [--update]")
+	}
+
+	prefix := "v"
+	if branchConfig := loadProjectConfig(); branchConfig != nil {
+		prefix = branchConfig.Finish.GetTagPrefix()
+	}
+
+	from, to, err := changelogRange(positional, prefix)
+	if err != nil {
+		return err
+	}
+	commits, err := git.Log(from, to)
+	if err != nil {
+		return err
+	}
+	keyPattern, err := git.GetIssueKeyPattern()
+	if err != nil {
+		return err
+	}
+	entries := changelog.FromCommits(commits, keyPattern)
+
+	if !*noJira {
+		if client, err := newJiraClient(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: not adding Jira details: %v\n", err)
+		} else {
+			enrichChangelog(client, entries)
+		}
+	}
+
+	notes := &changelog.Changelog{Version: *version, Date: *date, From: from, To: to}
+	if notes.Version == "" {
+		notes.Version = changelog.Unreleased
+		if v, err := git.ParseVersion(strings.TrimPrefix(to, prefix)); err == nil && git.TagExists(to) {
+			notes.Version = v.String()
+		}
+	}
+	if notes.Date == "" && notes.Version != changelog.Unreleased {
+		notes.Date = time.Now().Format(jira.ReleaseDateLayout)
+		if git.TagExists(to) && len(commits) > 0 {
+			notes.Date = commits[0].Date.Format(jira.ReleaseDateLayout)
+		}
+	}
+
+	if *update {
+		notes.Groups = changelog.KeepAChangelogGroups(entries)
+		var section bytes.Buffer
+		if err := notes.WriteMarkdown(&section); err != nil {
+			return err
+		}
+		path := *file
+		if !filepath.IsAbs(path) {
+			projectRoot, err := git.GetProjectRoot()
+			if err != nil {
+				return err
+			}
+			path = filepath.Join(projectRoot, path)
+		}
+		if err := changelog.UpdateFile(path, notes.Version, section.String()); err != nil {
+			return err
+		}
+		fmt.Printf("Updated %s with %s\n", *file, notes.Version)
+		return nil
+	}
+
+	if notes.Groups, err = changelog.GroupEntries(entries, *group); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "markdown", "md":
+		return notes.WriteMarkdown(w)
+	case "text":
+		return notes.WriteText(w)
+	case "json":
+		return notes.WriteJSON(w)
+	default:
+		return fmt.Errorf("invalid format %q, expected markdown, text or json", *format)
+	}
+}
+
+// changelogRange resolves "from..to", "from" or nothing (latest version tag
+// up to HEAD) into the range of commits to list.
+func changelogRange(positional []string, prefix string) (from, to string, err error) {
+	to = "HEAD"
+	if len(positional) == 1 {
+		from = positional[0]
+		if i := strings.Index(from, ".."); i >= 0 {
+			from, to = from[:i], from[i+2:]
+			if to == "" {
+				to = "HEAD"
+			}
+		}
+		return from, to, nil
+	}
+
+	latest, err := git.LatestVersionTag(prefix)
+	if err != nil {
+		return "", "", err
+	}
+	if latest != nil {
+		from = latest.Name
+	}
+	return from, to, nil
+}
+
+// enrichChangelog replaces commit descriptions with the live Jira summary
+// and adds links, issue types and epics. Issues that cannot be fetched keep
+// the commit description.
+func enrichChangelog(client *jira.Client, entries []changelog.Entry) {
+	issues := make(map[string]*jira.JiraIssue)
+	fetch := func(key string) *jira.JiraIssue {
+		issue, fetched := issues[key]
+		if !fetched {
+			var err error
+			if issue, err = client.FetchIssue(key); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", key, err)
+			}
+			issues[key] = issue
+		}
+		return issue
+	}
+
+	// Server and Data Center link epics through a custom field
+	epicLinkField, err := client.EpicLinkField()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not look up the Epic Link field: %v\n", err)
+	}
+	epicLinks := make(map[string]string)
+
+	for i := range entries {
+		key := entries[i].IssueKey
+		if key == "" {
+			continue
+		}
+		issue := fetch(key)
+
+		entries[i].URL = client.BrowseURL(key)
+		if issue == nil {
+			continue
+		}
+		if issue.Fields.Summary != "" {
+			entries[i].Title = issue.Fields.Summary
+		}
+		entries[i].IssueType = issue.Fields.IssueType.Name
+		if epic := issue.Epic(); epic != nil {
+			entries[i].EpicKey = epic.Key
+			entries[i].Epic = epic.Fields.Summary
+			continue
+		}
+		if epicLinkField == "" {
+			continue
+		}
+		epicKey, linked := epicLinks[key]
+		if !linked {
+			if epicKey, err = client.EpicLink(key, epicLinkField); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", key, err)
+			}
+			epicLinks[key] = epicKey
+		}
+		if epicKey == "" {
+			continue
+		}
+		entries[i].EpicKey = epicKey
+		if epic := fetch(epicKey); epic != nil {
+			entries[i].Epic = epic.Fields.Summary
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"jira-tools/internal/git"
+	"jira-tools/internal/jira"
+	"jira-tools/internal/markup"
+)
+
+// scissorsLine separates the comment from the instructions in the editor.
+// Everything below it is dropped, so Markdown headings survive.
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+func handleComment(args []string) error {
+	fs := flag.NewFlagSet("comment", flag.ContinueOnError)
+	fromCommits := fs.Bool("from-commits", false, "add a summary of the commits on the current branch")
+	edit := fs.Bool("edit", false, "open the editor with the comment before posting it")
+	dryRun := fs.Bool("dry-run", false, "print the converted comment instead of posting it")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	issueKey, rest, err := issueKeyArg(positional)
+	if err != nil {
+		return err
+	}
+
+	text := strings.Join(rest, " ")
+	if *fromCommits {
+		summary, err := commitSummary()
+		if err != nil {
+			return err
+		}
+		if text != "" {
+			text += "\n\n"
+		}
+		text += summary
+	}
+	if text == "" || *edit {
+		if text, err = editText(issueKey, text); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("aborting comment due to empty message")
+	}
+
+	// The rich text format follows the API version the client will use;
+	// --dry-run must not need working credentials to tell
+	var body interface{} = markup.MarkdownToWiki(text)
+	if os.Getenv("JIRA_API_VERSION") == "3" {
+		body = markup.MarkdownToADF(text)
+	}
+	if *dryRun {
+		if wiki, ok := body.(string); ok {
+			fmt.Println(wiki)
+			return nil
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(body)
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+	id, err := client.AddComment(issueKey, body)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added comment to %s: %s?focusedCommentId=%s\n", issueKey, client.BrowseURL(issueKey), id)
+	return nil
+}
+
+// commitSummary lists the commits of the current branch that are not on
+// the branch it will be merged into, as Markdown.
+func commitSummary() (string, error) {
+	branchConfig := loadProjectConfig()
+	if branchConfig == nil {
+		return "", fmt.Errorf("no project configuration found, run 'jt setup' first")
+	}
+	branch, err := git.GetCurrentBranch()
+	if err != nil {
+		return "", err
+	}
+	base := pullRequestBase(branchConfig, git.BranchTypeOf(branch))
+	commits, err := git.Log(git.BaseRef(base), "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf("no commits on %s since %s", branch, base)
+	}
+
+	noun := "commits"
+	if len(commits) == 1 {
+		noun = "commit"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s on `%s`:\n\n", len(commits), noun, branch)
+	now := time.Now()
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		hash := commit.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		fmt.Fprintf(&b, "- `%s` %s (%s, %s)\n", hash, commit.Subject, commit.Author, relativeTime(commit.Date, now))
+	}
+	return b.String(), nil
+}
+
+// editText opens $VISUAL or $EDITOR on text and returns what was saved,
+// without the instructions below the scissors line.
+func editText(issueKey, text string) (string, error) {
+	f, err := os.CreateTemp("", "jt-comment-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	instructions := fmt.Sprintf("%s\n# Write the comment on %s above this line in Markdown.\n"+
+		"# Everything below it is ignored. An empty comment aborts.\n", scissorsLine, issueKey)
+	if _, err := f.WriteString(text + "\n\n" + instructions); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// Run through the shell so editors with arguments such as "code --wait" work
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, editor, f.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %v", editor, err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	edited := string(data)
+	if i := strings.Index(edited, scissorsLine); i >= 0 {
+		edited = edited[:i]
+	}
+	return strings.TrimSpace(edited), nil
+}
+
+func handleComments(args []string) error {
+	fs := flag.NewFlagSet("comments", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	issueKey, rest, err := issueKeyArg(positional)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("usage: jt comments [card-number]")
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+	comments, err := client.Comments(issueKey)
+	if err != nil {
+		return err
+	}
+	if len(comments) == 0 {
+		fmt.Printf("No comments on %s\n", issueKey)
+		return nil
+	}
+
+	now := time.Now()
+	for i, comment := range comments {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s, %s\n", commentAuthor(comment), commentTime(comment, now))
+		for _, line := range strings.Split(strings.TrimSpace(comment.Body.Terminal()), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	return nil
+}
+
+func commentAuthor(comment jira.Comment) string {
+	if comment.Author == nil {
+		return "Anonymous"
+	}
+	if comment.Author.DisplayName != "" {
+		return comment.Author.DisplayName
+	}
+	return comment.Author.Name
+}
+
+func commentTime(comment jira.Comment, now time.Time) string {
+	created, err := comment.CreatedTime()
+	if err != nil {
+		return comment.Created
+	}
+	return relativeTime(created, now)
+}
+
+// relativeTime describes t relative to now, e.g. "3 hours ago". Times more
+// than a month ago are shown as a date.
+func relativeTime(t, now time.Time) string {
+	elapsed := now.Sub(t)
+	unit := func(n int, name string) string {
+		if n == 1 {
+			return "1 " + name + " ago"
+		}
+		return fmt.Sprintf("%d %ss ago", n, name)
+	}
+
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return unit(int(elapsed/time.Minute), "minute")
+	case elapsed < 24*time.Hour:
+		return unit(int(elapsed/time.Hour), "hour")
+	case elapsed < 30*24*time.Hour:
+		return unit(int(elapsed/(24*time.Hour)), "day")
+	default:
+		return t.Format("2 Jan 2006")
+	}
+}
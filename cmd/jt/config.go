@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/git"
+)
+
+const configUsage = `usage:
+  jt config show [--origin]  - Show the merged configuration (--origin: where each value comes from)`
+
+func handleConfig(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand\n%s", configUsage)
+	}
+	switch args[0] {
+	case "show":
+		return handleConfigShow(args[1:])
+	default:
+		return fmt.Errorf("unknown subcommand %q\n%s", args[0], configUsage)
+	}
+}
+
+// handleConfigShow prints the settings merged from all configuration
+// layers, optionally with the layer each one comes from.
+func handleConfigShow(args []string) error {
+	fs := flag.NewFlagSet("config show", flag.ContinueOnError)
+	origin := fs.Bool("origin", false, "show the file, variable or flag each value comes from")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	projectRoot, err := git.GetProjectRoot()
+	if err != nil {
+		projectRoot = ""
+	}
+	resolution, err := config.Resolve(projectRoot, config.Overrides)
+	if err != nil {
+		return err
+	}
+
+	if *origin {
+		fmt.Println("Layers, lowest precedence first:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, layer := range resolution.Layers {
+			fmt.Fprintf(w, "  %s\t%s\n", layer.Layer, layer.Source)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, setting := range flattenSettings(resolution.Values, "") {
+		if *origin {
+			from := resolution.Origins[setting.key]
+			fmt.Fprintf(w, "%s\t%s\t%s (%s)\n", setting.key, setting.value, from.Layer, from.Source)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\n", setting.key, setting.value)
+		}
+	}
+	return w.Flush()
+}
+
+type shownSetting struct {
+	key   string
+	value string
+}
+
+// flattenSettings lists the settings by dotted key, sorted. Lists are shown
+// as JSON.
+func flattenSettings(values map[string]interface{}, prefix string) []shownSetting {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var settings []shownSetting
+	for _, key := range keys {
+		switch value := values[key].(type) {
+		case map[string]interface{}:
+			settings = append(settings, flattenSettings(value, prefix+key+".")...)
+		case string:
+			if value == "" {
+				value = `""`
+			}
+			settings = append(settings, shownSetting{prefix + key, value})
+		default:
+			data, err := json.Marshal(value)
+			if err != nil {
+				data = []byte(fmt.Sprint(value))
+			}
+			settings = append(settings, shownSetting{prefix + key, strings.TrimSpace(string(data))})
+		}
+	}
+	return settings
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/credentials"
+	"jira-tools/internal/jira"
+	"github.com/joho/godotenv"
+)
+
+// credentialStoreEnv selects where the secrets are kept. Unset means the
+// .env file itself.
+const credentialStoreEnv = "JIRA_CREDENTIAL_STORE"
+
+// oauthTokenKey holds the OAuth 2.0 access and refresh tokens, as JSON.
+const oauthTokenKey = "JIRA_OAUTH_TOKEN"
+
+// secretKeys are the settings kept in the credential store rather than in
+// the .env file.
+var secretKeys = []string{"JIRA_API_TOKEN", "JIRA_OAUTH_CLIENT_SECRET", oauthTokenKey}
+
+// authSecrets returns the secrets an authentication method needs.
+func authSecrets(method string) []string {
+	if method == authOAuth {
+		return []string{"JIRA_OAUTH_CLIENT_SECRET", oauthTokenKey}
+	}
+	return []string{"JIRA_API_TOKEN"}
+}
+
+func isSecretKey(key string) bool {
+	for _, secret := range secretKeys {
+		if key == secret {
+			return true
+		}
+	}
+	return false
+}
+
+func credentialOptions() (credentials.Options, error) {
+	configDir, err := config.GetConfigPath()
+	if err != nil {
+		return credentials.Options{}, err
+	}
+	envPath, err := config.GetEnvPath(activeProfile)
+	if err != nil {
+		return credentials.Options{}, err
+	}
+	return credentials.Options{
+		EnvPath:    envPath,
+		FilePath:   filepath.Join(configDir, "credentials.enc"),
+		Passphrase: promptPassphrase,
+		Profile:    activeProfile,
+	}, nil
+}
+
+// newCredentialStore opens the credential store of a kind for the active
+// profile. Tests replace it with a credentials.MemoryStore.
+var newCredentialStore = func(kind string) (credentials.Store, error) {
+	if kind == "" {
+		kind = credentials.Env
+	}
+	opts, err := credentialOptions()
+	if err != nil {
+		return nil, err
+	}
+	return credentials.New(kind, opts)
+}
+
+// promptPassphrase asks for the passphrase of the encrypted credentials
+// file, unless JT_CREDENTIALS_PASSPHRASE provides it.
+func promptPassphrase(create bool) (string, error) {
+	if passphrase := os.Getenv("JT_CREDENTIALS_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	if !create {
+		return promptSecret("Passphrase for ~/.jira-tools/credentials.enc: "), nil
+	}
+	passphrase := promptSecret("New passphrase for ~/.jira-tools/credentials.enc: ")
+	if promptSecret("Repeat the passphrase: ") != passphrase {
+		return "", fmt.Errorf("the passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// promptSecret prompts like promptUser without echoing the input, where
+// the terminal allows it.
+func promptSecret(message string) string {
+	if setEcho(false) != nil {
+		return promptUser(message)
+	}
+	defer setEcho(true)
+	input := promptUser(message)
+	fmt.Println()
+	return input
+}
+
+func setEcho(on bool) error {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	cmd := exec.Command("stty", mode)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// loadSecrets fills in the secrets of the configured authentication method
+// from the credential store. Secrets already set in the environment take
+// precedence.
+func loadSecrets() error {
+	kind := os.Getenv(credentialStoreEnv)
+	if kind == "" || kind == credentials.Env {
+		return nil
+	}
+	store, err := newCredentialStore(kind)
+	if err != nil {
+		return err
+	}
+	for _, key := range authSecrets(os.Getenv("JIRA_AUTH")) {
+		if os.Getenv(key) != "" {
+			continue
+		}
+		value, err := store.Get(key)
+		if errors.Is(err, credentials.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s from the %s: %v", key, store.Name(), err)
+		}
+		os.Setenv(key, value)
+	}
+	return nil
+}
+
+// chooseCredentialStore asks where to keep the secrets, offering the stores
+// available on this machine.
+func chooseCredentialStore() (string, error) {
+	var kinds, names []string
+	for _, kind := range credentials.Kinds() {
+		if credentials.Available(kind) {
+			kinds = append(kinds, kind)
+			names = append(names, credentials.Describe(kind))
+		}
+	}
+	fmt.Println("\nWhere should the credentials be stored?")
+	index, err := chooseOption(names)
+	if err != nil {
+		return "", err
+	}
+	return kinds[index], nil
+}
+
+// storeSecrets moves the secrets of settings into the store of the given
+// kind and returns the settings to write to the .env file.
+func storeSecrets(kind string, settings envSettings) (envSettings, error) {
+	if kind == credentials.Env {
+		return settings, nil
+	}
+	store, err := newCredentialStore(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept envSettings
+	for _, setting := range settings {
+		if !isSecretKey(setting.Key) {
+			kept = append(kept, setting)
+			continue
+		}
+		if setting.Value == "" {
+			continue
+		}
+		if err := store.Set(setting.Key, setting.Value); err != nil {
+			return nil, fmt.Errorf("failed to save %s in the %s: %v", setting.Key, store.Name(), err)
+		}
+	}
+	return append(kept, envSetting{credentialStoreEnv, kind}), nil
+}
+
+func handleAuth(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: jt auth <login|logout|status|list>")
+	}
+	switch args[0] {
+	case "list":
+		return authList()
+	case "login":
+		return authLogin(args[1:])
+	case "logout":
+		return authLogout()
+	case "status":
+		return authStatus()
+	default:
+		return fmt.Errorf("unknown auth command %q, expected login, logout, status or list", args[0])
+	}
+}
+
+// authLogin saves new credentials, or with --migrate the ones in the .env
+// file, in a credential store.
+func authLogin(args []string) error {
+	fs := flag.NewFlagSet("auth login", flag.ContinueOnError)
+	storeKind := fs.String("store", "", "credential store: secret-service, pass, file or env (default: asked)")
+	migrate := fs.Bool("migrate", false, "move the secrets in the .env file of the profile instead of entering new ones")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	envPath, err := config.GetEnvPath(activeProfile)
+	if err != nil {
+		return err
+	}
+	if os.Getenv("JIRA_DOMAIN") == "" {
+		return fmt.Errorf("JIRA_DOMAIN is not set, run 'jt %ssetup' first", profileFlag())
+	}
+
+	kind := *storeKind
+	if kind == "" {
+		if kind, err = chooseCredentialStore(); err != nil {
+			return err
+		}
+	}
+	store, err := newCredentialStore(kind)
+	if err != nil {
+		return err
+	}
+	if !credentials.Available(kind) {
+		return fmt.Errorf("the %s is not available on this machine", store.Name())
+	}
+
+	secrets := make(map[string]string)
+	if *migrate {
+		values, err := godotenv.Read(envPath)
+		if err != nil {
+			return err
+		}
+		for _, key := range secretKeys {
+			if values[key] != "" {
+				secrets[key] = values[key]
+			}
+		}
+		if len(secrets) == 0 {
+			return fmt.Errorf("no credentials to migrate in %s", envPath)
+		}
+	} else {
+		switch os.Getenv("JIRA_AUTH") {
+		case authPAT:
+			secrets["JIRA_API_TOKEN"] = promptSecret("Personal Access Token: ")
+		case authOAuth:
+			secrets["JIRA_OAUTH_CLIENT_SECRET"] = promptSecret("OAuth Client Secret: ")
+			os.Setenv("JIRA_OAUTH_CLIENT_SECRET", secrets["JIRA_OAUTH_CLIENT_SECRET"])
+			token, err := oauthLogin(oauthConfig(os.Getenv), os.Getenv("JIRA_DOMAIN"))
+			if err != nil {
+				return fmt.Errorf("OAuth login failed: %v", err)
+			}
+			if secrets[oauthTokenKey], err = encodeOAuthToken(token); err != nil {
+				return err
+			}
+		default:
+			secrets["JIRA_API_TOKEN"] = promptSecret("Jira API Token (or password): ")
+		}
+	}
+
+	for key, value := range secrets {
+		os.Setenv(key, value)
+	}
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+	if err := client.ValidateCredentials(); err != nil {
+		return fmt.Errorf("credential validation failed: %v", err)
+	}
+
+	previous := os.Getenv(credentialStoreEnv)
+	updates := map[string]string{credentialStoreEnv: kind}
+	if kind == credentials.Env {
+		updates[credentialStoreEnv] = ""
+	}
+	for key, value := range secrets {
+		if err := store.Set(key, value); err != nil {
+			return fmt.Errorf("failed to save %s in the %s: %v", key, store.Name(), err)
+		}
+		if kind != credentials.Env {
+			// Drop the plain text copy
+			updates[key] = ""
+		}
+	}
+	if err := config.UpdateEnvFile(envPath, updates); err != nil {
+		return err
+	}
+
+	if previous != "" && previous != credentials.Env && previous != kind {
+		if old, err := newCredentialStore(previous); err == nil {
+			for key := range secrets {
+				if err := old.Delete(key); err != nil {
+					fmt.Printf("Warning: could not remove %s from the %s: %v\n", key, old.Name(), err)
+				}
+			}
+		}
+	}
+	fmt.Printf("Saved Jira credentials in the %s\n", store.Name())
+	return nil
+}
+
+// authLogout removes the secrets from the credential store and the .env
+// file, keeping the other connection settings.
+func authLogout() error {
+	store, err := newCredentialStore(os.Getenv(credentialStoreEnv))
+	if err != nil {
+		return err
+	}
+	for _, key := range secretKeys {
+		if err := store.Delete(key); err != nil {
+			return fmt.Errorf("failed to remove %s from the %s: %v", key, store.Name(), err)
+		}
+	}
+
+	envPath, err := config.GetEnvPath(activeProfile)
+	if err != nil {
+		return err
+	}
+	updates := make(map[string]string)
+	for _, key := range secretKeys {
+		updates[key] = ""
+	}
+	if err := config.UpdateEnvFile(envPath, updates); err != nil {
+		return err
+	}
+	fmt.Printf("Removed Jira credentials from the %s\n", store.Name())
+	return nil
+}
+
+func authStatus() error {
+	if os.Getenv("JIRA_DOMAIN") == "" {
+		fmt.Printf("Profile %s is not configured, run 'jt %ssetup' first\n", activeProfile, profileFlag())
+		return nil
+	}
+	kind := os.Getenv(credentialStoreEnv)
+	if kind == "" {
+		kind = credentials.Env
+	}
+
+	fmt.Printf("Profile: %s\n", activeProfile)
+	fmt.Printf("Jira URL: %s\n", os.Getenv("JIRA_DOMAIN"))
+	fmt.Printf("Authentication: %s\n", authMethodName(os.Getenv("JIRA_AUTH")))
+	fmt.Printf("Credential store: %s\n", credentials.Describe(kind))
+
+	client, err := newJiraClient()
+	if err == nil {
+		var user *jira.User
+		if user, err = client.Myself(); err == nil {
+			fmt.Printf("Logged in as %s", user.DisplayName)
+			if user.EmailAddress != "" {
+				fmt.Printf(" (%s)", user.EmailAddress)
+			}
+			fmt.Println()
+		}
+	}
+	if err != nil {
+		fmt.Printf("Not logged in: %v\n", err)
+	}
+
+	if kind == credentials.Env {
+		for _, key := range secretKeys {
+			if os.Getenv(key) != "" {
+				fmt.Printf("\n%s is stored in plain text in the .env file.\n", key)
+				fmt.Printf("Run 'jt %sauth login --migrate' to move it to a safer credential store.\n", profileFlag())
+				break
+			}
+		}
+	}
+	return nil
+}
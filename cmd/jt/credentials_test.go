@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"jira-tools/internal/credentials"
+)
+
+// useMemoryStore makes every credential store kind open the same
+// MemoryStore for the duration of the test.
+func useMemoryStore(t *testing.T) *credentials.MemoryStore {
+	t.Helper()
+	store := credentials.NewMemoryStore()
+	saved := newCredentialStore
+	newCredentialStore = func(string) (credentials.Store, error) { return store, nil }
+	t.Cleanup(func() { newCredentialStore = saved })
+	return store
+}
+
+// setenv sets an environment variable until the end of the test.
+func setenv(t *testing.T, key, value string) {
+	t.Helper()
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestStoreSecrets(t *testing.T) {
+	settings := envSettings{
+		{"JIRA_DOMAIN", "acme.atlassian.net"},
+		{"JIRA_API_TOKEN", "secret-token"},
+		{"JIRA_EMAIL", "dev@example.com"},
+		{"JIRA_OAUTH_CLIENT_SECRET", ""},
+	}
+
+	t.Run("env store keeps everything", func(t *testing.T) {
+		store := useMemoryStore(t)
+		kept, err := storeSecrets(credentials.Env, settings)
+		if err != nil {
+			t.Fatalf("storeSecrets: %v", err)
+		}
+		if len(kept) != len(settings) || len(store.Secrets) != 0 {
+			t.Errorf("kept %v and stored %v, want the settings unchanged", kept, store.Secrets)
+		}
+	})
+
+	t.Run("secrets moved to the store", func(t *testing.T) {
+		store := useMemoryStore(t)
+		kept, err := storeSecrets(credentials.Pass, settings)
+		if err != nil {
+			t.Fatalf("storeSecrets: %v", err)
+		}
+		want := envSettings{
+			{"JIRA_DOMAIN", "acme.atlassian.net"},
+			{"JIRA_EMAIL", "dev@example.com"},
+			{credentialStoreEnv, credentials.Pass},
+		}
+		if len(kept) != len(want) {
+			t.Fatalf("kept %v, want %v", kept, want)
+		}
+		for i := range want {
+			if kept[i] != want[i] {
+				t.Errorf("kept[%d] = %v, want %v", i, kept[i], want[i])
+			}
+		}
+		if len(store.Secrets) != 1 || store.Secrets["JIRA_API_TOKEN"] != "secret-token" {
+			t.Errorf("stored %v, want only the API token", store.Secrets)
+		}
+	})
+}
+
+func TestLoadSecrets(t *testing.T) {
+	tests := []struct {
+		name   string
+		store  string
+		auth   string
+		env    map[string]string
+		stored map[string]string
+		want   map[string]string
+	}{
+		{
+			name:   "API token from the store",
+			store:  credentials.Pass,
+			stored: map[string]string{"JIRA_API_TOKEN": "stored-token"},
+			want:   map[string]string{"JIRA_API_TOKEN": "stored-token"},
+		},
+		{
+			name:   "environment wins",
+			store:  credentials.Pass,
+			env:    map[string]string{"JIRA_API_TOKEN": "env-token"},
+			stored: map[string]string{"JIRA_API_TOKEN": "stored-token"},
+			want:   map[string]string{"JIRA_API_TOKEN": "env-token"},
+		},
+		{
+			name:   "OAuth secrets only",
+			store:  credentials.File,
+			auth:   authOAuth,
+			stored: map[string]string{"JIRA_API_TOKEN": "stored-token", "JIRA_OAUTH_CLIENT_SECRET": "client-secret", oauthTokenKey: `{"access_token":"a"}`},
+			want:   map[string]string{"JIRA_API_TOKEN": "", "JIRA_OAUTH_CLIENT_SECRET": "client-secret", oauthTokenKey: `{"access_token":"a"}`},
+		},
+		{
+			name:   "missing secret",
+			store:  credentials.Pass,
+			stored: map[string]string{},
+			want:   map[string]string{"JIRA_API_TOKEN": ""},
+		},
+		{
+			name:   "env store reads nothing",
+			store:  credentials.Env,
+			stored: map[string]string{"JIRA_API_TOKEN": "stored-token"},
+			want:   map[string]string{"JIRA_API_TOKEN": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := useMemoryStore(t)
+			store.Secrets = tt.stored
+			setenv(t, credentialStoreEnv, tt.store)
+			setenv(t, "JIRA_AUTH", tt.auth)
+			for _, key := range secretKeys {
+				setenv(t, key, tt.env[key])
+			}
+
+			if err := loadSecrets(); err != nil {
+				t.Fatalf("loadSecrets: %v", err)
+			}
+			for key, want := range tt.want {
+				if got := os.Getenv(key); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/git"
+)
+
+// finishFlags are the flags shared by `jt finish` and `jt release finish`.
+type finishFlags struct {
+	noFF, ff, squash  *bool
+	message, tag      *string
+	noTag, keep, push *bool
+	noTransition      *bool
+}
+
+func addFinishFlags(fs *flag.FlagSet) *finishFlags {
+	return &finishFlags{
+		noFF:         fs.Bool("no-ff", false, "always create a merge commit"),
+		ff:           fs.Bool("ff", false, "fast-forward when possible"),
+		squash:       fs.Bool("squash", false, "squash the branch into a single commit"),
+		message:      fs.String("m", "", "merge commit message"),
+		tag:          fs.String("tag", "", "tag name for release and hotfix branches"),
+		noTag:        fs.Bool("no-tag", false, "do not tag release and hotfix branches"),
+		keep:         fs.Bool("keep", false, "keep the branch after merging"),
+		push:         fs.Bool("push", false, "push the merged branches and tag to origin"),
+		noTransition: fs.Bool("no-transition", false, "skip configured transition rules"),
+	}
+}
+
+func handleFinish(args []string) error {
+	fs := flag.NewFlagSet("finish", flag.ContinueOnError)
+	flags := addFinishFlags(fs)
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("usage: jt finish [branch] [--no-ff|--ff|--squash] [--tag <name>|--no-tag] [--keep] [--push]")
+	}
+
+	branchConfig := loadProjectConfig()
+	if branchConfig == nil {
+		return fmt.Errorf("no project configuration found, run 'jt setup' first")
+	}
+
+	branch := ""
+	if len(positional) == 1 {
+		branch = positional[0]
+	} else if branch, err = git.GetCurrentBranch(); err != nil {
+		return err
+	}
+	return finishBranch(branchConfig, branch, flags)
+}
+
+// finishBranch merges branch into its targets, tags releases and hotfixes
+// and runs the finish transition rules.
+func finishBranch(branchConfig *config.BranchConfig, branch string, flags *finishFlags) error {
+	branchType := git.BranchTypeOf(branch)
+	targets, err := git.FinishTargets(branchConfig, branchType)
+	if err != nil {
+		return err
+	}
+
+	opts := git.FinishOptions{
+		Strategy:   branchConfig.Finish.Merge,
+		Message:    *flags.message,
+		Keep:       *flags.keep,
+		KeepRemote: branchConfig.Finish.KeepRemote,
+		Push:       *flags.push,
+	}
+	switch {
+	case *flags.noFF && (*flags.ff || *flags.squash), *flags.ff && *flags.squash:
+		return fmt.Errorf("--no-ff, --ff and --squash cannot be combined")
+	case *flags.noFF:
+		opts.Strategy = config.MergeNoFF
+	case *flags.ff:
+		opts.Strategy = config.MergeFF
+	case *flags.squash:
+		opts.Strategy = config.MergeSquash
+	}
+
+	if (branchType == git.ReleaseBranch || branchType == git.HotfixBranch) && !*flags.noTag {
+		opts.Tag = *flags.tag
+		if opts.Tag == "" {
+			if opts.Tag, err = defaultFinishTag(branch, branchType, branchConfig.Finish.GetTagPrefix()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := git.FinishBranch(branch, targets, opts); err != nil {
+		return err
+	}
+
+	issueKey := git.IssueKeyFromBranch(branch)
+	if *flags.noTransition || issueKey == "" {
+		return nil
+	}
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+	runTransitionRules(client, config.EventFinish, issueKey, branchType)
+	return nil
+}
+
+// defaultFinishTag names the tag of a finished release or hotfix branch.
+// Branches named after a version (release/1.4.0) use that version; others
+// get the next minor (release) or patch (hotfix) version after the latest tag.
+func defaultFinishTag(branch string, branchType git.BranchType, prefix string) (string, error) {
+	name := strings.TrimPrefix(branch, string(branchType)+"/")
+	if version, err := git.ParseVersion(name); err == nil {
+		return prefix + version.String(), nil
+	}
+
+	var latest git.Version
+	tag, err := git.LatestVersionTag(prefix)
+	if err != nil {
+		return "", err
+	}
+	if tag != nil {
+		latest = tag.Version
+	}
+	part := "patch"
+	if branchType == git.ReleaseBranch {
+		part = "minor"
+	}
+	next, err := latest.Bump(part)
+	if err != nil {
+		return "", err
+	}
+	return prefix + next.String(), nil
+}
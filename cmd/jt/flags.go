@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"jira-tools/internal/config"
+)
+
+// settingFlag collects `-c key=value` overrides of configuration settings.
+type settingFlag map[string]string
+
+func (f settingFlag) String() string { return "" }
+
+func (f settingFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	f[strings.TrimSpace(parts[0])] = parts[1]
+	return nil
+}
+
+// parseGlobalFlags parses the flags given before the command, such as
+// `jt --profile client lookup` or `jt -c commit_mode=all commit`, into
+// config.Overrides. It returns the command and its arguments.
+func parseGlobalFlags(args []string) ([]string, error) {
+	fs := flag.NewFlagSet("jt", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	profile := fs.String("profile", "", "Jira profile to use")
+	fs.Var(settingFlag(config.Overrides.Flags), "c", "override a configuration setting (key=value)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *profile != "" {
+		config.Overrides.Flags["profile"] = *profile
+	}
+	return fs.Args(), nil
+}
+
+// parseArgs parses flags that may appear before, between or after
+// positional arguments and returns the positional arguments in order.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	return positional, nil
+}
+
+// splitList splits a comma separated flag value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/git"
+	"jira-tools/internal/jira"
+)
+
+const hooksUsage = `Usage:
+  jt hooks install [hook...]    - Install commit hooks (default: prepare-commit-msg, commit-msg)
+  jt hooks uninstall [hook...]  - Remove jt hooks and restore the hooks they chained
+  jt hooks status               - Show which jt hooks are installed`
+
+// commitHooks are managed by `jt hooks` and run `jt hooks run <name>`.
+var commitHooks = []string{"prepare-commit-msg", "commit-msg"}
+
+func handleHooks(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand\n%s", hooksUsage)
+	}
+
+	names := args[1:]
+	if len(names) == 0 {
+		names = commitHooks
+	}
+
+	switch args[0] {
+	case "install":
+		for _, name := range names {
+			if !isCommitHook(name) {
+				return fmt.Errorf("unknown hook %q (available: %s)", name, strings.Join(commitHooks, ", "))
+			}
+			body := git.HookBody(fmt.Sprintf("hooks run %s \"$@\"", name))
+			if err := git.InstallHook(name, body); err != nil {
+				return err
+			}
+			fmt.Printf("Installed %s hook\n", name)
+		}
+		return nil
+
+	case "uninstall":
+		for _, name := range names {
+			installed, err := git.HookInstalled(name)
+			if err != nil {
+				return err
+			}
+			if !installed {
+				continue
+			}
+			if err := git.UninstallHook(name); err != nil {
+				return err
+			}
+			fmt.Printf("Removed %s hook\n", name)
+		}
+		return nil
+
+	case "status":
+		hooksDir, err := git.GetHooksDir()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Hooks directory: %s\n", hooksDir)
+		for _, name := range append(commitHooks, "post-checkout", "post-commit") {
+			installed, err := git.HookInstalled(name)
+			if err != nil {
+				return err
+			}
+			status := "not installed"
+			if installed {
+				status = "installed"
+			}
+			fmt.Printf("  %-20s %s\n", name, status)
+		}
+		return nil
+
+	case "run":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: jt hooks run <hook> <message-file> [hook arguments]")
+		}
+		switch args[1] {
+		case "prepare-commit-msg":
+			return runPrepareCommitMsg(args[2:])
+		case "commit-msg":
+			return runCommitMsg(args[2])
+		}
+		return fmt.Errorf("unknown hook %q", args[1])
+
+	default:
+		return fmt.Errorf("unknown subcommand %q\n%s", args[0], hooksUsage)
+	}
+}
+
+func isCommitHook(name string) bool {
+	for _, hook := range commitHooks {
+		if hook == name {
+			return true
+		}
+	}
+	return false
+}
+
+// runPrepareCommitMsg adds the issue key of the current branch to the
+// commit message git is about to show or use.
+func runPrepareCommitMsg(args []string) error {
+	msgFile := args[0]
+	if len(args) > 1 {
+		switch args[1] {
+		case "merge", "squash", "commit":
+			// Generated or reused messages are left alone
+			return nil
+		}
+	}
+
+	branch, err := git.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+	issueKey := git.IssueKeyFromBranch(branch)
+	if issueKey == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(msgFile)
+	if err != nil {
+		return err
+	}
+	message := string(content)
+	if git.IsGeneratedCommitMessage(message) {
+		return nil
+	}
+
+	updated := git.InsertIssueKey(message, issueKey)
+	if updated == message {
+		return nil
+	}
+	return os.WriteFile(msgFile, []byte(updated), 0644)
+}
+
+// runCommitMsg rejects commit messages that do not follow the project's
+// convention.
+func runCommitMsg(msgFile string) error {
+	content, err := os.ReadFile(msgFile)
+	if err != nil {
+		return err
+	}
+	message := git.CleanCommitMessage(string(content))
+	if message == "" || git.IsGeneratedCommitMessage(message) {
+		return nil
+	}
+	subject := strings.SplitN(message, "\n", 2)[0]
+
+	var hooks config.HooksConfig
+	var commitTypes config.CommitTypesConfig
+	if branchConfig := loadProjectConfig(); branchConfig != nil {
+		hooks = branchConfig.Hooks
+		commitTypes = branchConfig.CommitTypes
+	}
+	require := hooks.Require
+	if require == "" {
+		require = config.RequireKey
+	}
+
+	if require == config.RequireConventional || require == config.RequireBoth {
+		commitType := git.ConventionalType(subject)
+		if commitType == "" {
+			return fmt.Errorf("commit message must start with a conventional type, e.g. \"feat(PROJ-123): add login\"\nMessage: %s", subject)
+		}
+		if err := commitTypes.Validate(commitType); err != nil {
+			return err
+		}
+	}
+
+	if require == config.RequireKey || require == config.RequireBoth {
+		re, err := git.GetIssueKeyPattern()
+		if err != nil {
+			return err
+		}
+		issueKey := re.FindString(message)
+		if issueKey == "" {
+			return fmt.Errorf("commit message must reference a Jira issue, e.g. \"PROJ-123: add login\"\nMessage: %s", subject)
+		}
+		if hooks.VerifyKey {
+			return verifyIssueExists(issueKey)
+		}
+	}
+	return nil
+}
+
+// verifyIssueExists rejects keys Jira does not know. Other failures, such
+// as being offline, only produce a warning so commits are not blocked.
+func verifyIssueExists(issueKey string) error {
+	client, err := newJiraClient()
+	if err != nil {
+		fmt.Printf("Warning: could not verify %s: %v\n", issueKey, err)
+		return nil
+	}
+
+	if _, err := client.FetchIssue(issueKey); err != nil {
+		var apiErr *jira.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("issue %s does not exist in Jira", issueKey)
+		}
+		fmt.Printf("Warning: could not verify %s: %v\n", issueKey, err)
+	}
+	return nil
+}
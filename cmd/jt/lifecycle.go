@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/git"
+	"jira-tools/internal/jira"
+)
+
+// runTransitionRules applies the transition rules configured for a lifecycle
+// event. The git operation has already succeeded at this point, so failures
+// are reported as warnings instead of errors.
+func runTransitionRules(client *jira.Client, event, issueKey string, branchType git.BranchType) {
+	projectRoot, err := git.GetProjectRoot()
+	if err != nil {
+		return
+	}
+	// Without this the status silently stays put when the configuration
+	// cannot be read
+	resolution, err := config.Resolve(projectRoot, config.Overrides)
+	if err != nil {
+		fmt.Printf("Warning: could not load the transition rules, %s was not updated: %v\n", issueKey, err)
+		return
+	}
+	if !resolution.HasProjectFile() {
+		return
+	}
+
+	for _, rule := range resolution.Config.RulesFor(event, string(branchType)) {
+		if rule.To != "" {
+			opts := transitionOptions{Resolution: rule.Resolution}
+			if err := transitionIssue(client, issueKey, rule.To, opts); err != nil {
+				fmt.Printf("Warning: could not move %s to %s: %v\n", issueKey, rule.To, err)
+			}
+		}
+		if rule.Assign != "" {
+			if err := assignIssue(client, issueKey, rule.Assign); err != nil {
+				fmt.Printf("Warning: could not assign %s: %v\n", issueKey, err)
+			}
+		}
+	}
+}
+
+// assignIssue assigns an issue to "me" or to the given account ID.
+func assignIssue(client *jira.Client, issueKey, assignee string) error {
+	user := &jira.User{AccountID: assignee}
+	if assignee == "me" {
+		var err error
+		if user, err = client.Myself(); err != nil {
+			return err
+		}
+	}
+
+	if err := client.AssignIssue(issueKey, user); err != nil {
+		return err
+	}
+
+	name := user.DisplayName
+	if name == "" {
+		name = assignee
+	}
+	fmt.Printf("Assigned %s to %s\n", issueKey, name)
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+
+	"jira-tools/internal/git"
+	"jira-tools/internal/hosting"
+	"jira-tools/internal/jira"
+)
+
+// remoteLinkApplication groups the links jt creates in the issue view.
+const remoteLinkApplication = "jira-tools"
+
+// linkBranch links the issue to the web page of a pushed branch. The push
+// has already succeeded, so failures are reported as warnings.
+func linkBranch(client *jira.Client, issueKey, branch string) {
+	remote, err := git.GetRemote("origin")
+	if err != nil {
+		fmt.Printf("Warning: could not link %s to %s: %v\n", branch, issueKey, err)
+		return
+	}
+
+	kind := hostingKind(remote.Host)
+	repoURL := hosting.RepositoryURL(remote)
+	link := jira.RemoteLink{
+		GlobalID:     "jira-tools:branch:" + repoURL + ":" + branch,
+		Application:  &jira.RemoteLinkApplication{Type: remoteLinkApplication, Name: hosting.ServiceName(kind)},
+		Relationship: "branch",
+		Object: jira.RemoteLinkObject{
+			URL:     hosting.BranchURL(kind, repoURL, branch),
+			Title:   branch,
+			Summary: remote.Path,
+		},
+	}
+	addRemoteLink(client, issueKey, link)
+}
+
+// linkPullRequest links the issue to a created pull request.
+func linkPullRequest(client *jira.Client, issueKey, kind string, created *hosting.Created, title string) {
+	if created.URL == "" {
+		return
+	}
+	link := jira.RemoteLink{
+		GlobalID:     "jira-tools:pr:" + created.URL,
+		Application:  &jira.RemoteLinkApplication{Type: remoteLinkApplication, Name: hosting.ServiceName(kind)},
+		Relationship: "pull request",
+		Object: jira.RemoteLinkObject{
+			URL:   created.URL,
+			Title: fmt.Sprintf("#%d %s", created.Number, title),
+		},
+	}
+	addRemoteLink(client, issueKey, link)
+}
+
+func addRemoteLink(client *jira.Client, issueKey string, link jira.RemoteLink) {
+	if err := client.CreateRemoteLink(issueKey, link); err != nil {
+		fmt.Printf("Warning: could not link %s to %s: %v\n", link.Object.Title, issueKey, err)
+		return
+	}
+	fmt.Printf("Linked %s to %s\n", link.Object.URL, issueKey)
+}
+
+// hostingKind returns the hosting service configured for the project, or
+// the one guessed from the remote host.
+func hostingKind(host string) string {
+	if branchConfig := loadProjectConfig(); branchConfig != nil && branchConfig.PullRequest.Provider != "" {
+		return branchConfig.PullRequest.Provider
+	}
+	return hosting.DetectKind(host)
+}
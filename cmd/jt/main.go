@@ -2,7 +2,9 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -14,25 +16,126 @@ import (
 	"github.com/joho/godotenv"
 )
 
-func loadConfig() error {
-	configDir, err := config.GetConfigPath()
+// loadConfig loads the connection settings of a Jira profile into the
+// environment.
+func loadConfig(profile string) error {
+	envPath, err := config.GetEnvPath(profile)
 	if err != nil {
 		return err
 	}
 
-	envPath := filepath.Join(configDir, ".env")
+	// A missing .env is fine: setup creates it, and commands that need
+	// credentials report it when building the Jira client.
+	if _, err := os.Stat(envPath); os.IsNotExist(err) {
+		return nil
+	}
 	return godotenv.Load(envPath)
 }
 
+// newJiraClient builds a Jira client from the settings loaded into the
+// environment by loadConfig and the secrets in the credential store.
+func newJiraClient() (*jira.Client, error) {
+	if err := loadSecrets(); err != nil {
+		return nil, err
+	}
+	opts, err := jiraOptions(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	return jira.NewClient(opts)
+}
+
+// validateCredentials checks freshly entered setup settings against Jira.
+func validateCredentials(settings envSettings) error {
+	opts, err := jiraOptions(settings.Get)
+	if err != nil {
+		return err
+	}
+	client, err := jira.NewClient(opts)
+	if err != nil {
+		return err
+	}
+	return client.ValidateCredentials()
+}
+
+// issueKeyArg takes the issue key from the first argument when it is one,
+// and from the current branch name otherwise. It returns the remaining
+// arguments.
+func issueKeyArg(args []string) (string, []string, error) {
+	if len(args) > 0 && git.IsIssueKey(args[0]) {
+		return args[0], args[1:], nil
+	}
+
+	issueKey, err := git.GetCurrentIssueKey()
+	if err != nil {
+		return "", nil, err
+	}
+	return issueKey, args, nil
+}
+
+// stdin is shared by the prompts, so input buffered for one prompt is not
+// lost for the next when stdin is piped.
+var stdin = bufio.NewReader(os.Stdin)
+
+// maxPromptAttempts bounds how often a prompt asks again after an invalid
+// answer.
+const maxPromptAttempts = 3
+
 func promptUser(message string) string {
-	reader := bufio.NewReader(os.Stdin)
+	input, _ := promptInput(message)
+	return input
+}
+
+// promptInput is promptUser returning io.EOF once stdin is closed.
+func promptInput(message string) (string, error) {
 	fmt.Print(message)
-	input, _ := reader.ReadString('\n')
-	return strings.TrimSpace(input)
+	input, err := stdin.ReadString('\n')
+	if err == io.EOF && input != "" {
+		err = nil
+	}
+	return strings.TrimSpace(input), err
+}
+
+// chooseOption lists options and returns the index of the one picked. It
+// fails when stdin is closed or after repeated invalid answers.
+func chooseOption(options []string) (int, error) {
+	for i, option := range options {
+		fmt.Printf("%d. %s\n", i+1, option)
+	}
+	for attempt := 1; ; attempt++ {
+		input, err := promptInput(fmt.Sprintf("Select option (1-%d): ", len(options)))
+		if err != nil {
+			fmt.Println()
+			return 0, fmt.Errorf("no option selected: %v", err)
+		}
+		if index, err := strconv.Atoi(input); err == nil && index > 0 && index <= len(options) {
+			return index - 1, nil
+		}
+		if attempt == maxPromptAttempts {
+			return 0, fmt.Errorf("no valid option selected after %d attempts", maxPromptAttempts)
+		}
+		fmt.Println("Invalid option. Please try again.")
+	}
 }
 
 func main() {
-	if err := loadConfig(); err != nil {
+	args, err := parseGlobalFlags(os.Args[1:])
+	if err == flag.ErrHelp {
+		printUsage()
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		printUsage()
+		os.Exit(1)
+	}
+	os.Args = append(os.Args[:1], args...)
+
+	if activeProfile, err = selectProfile(); err != nil {
+		fmt.Printf("Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if err := loadConfig(activeProfile); err != nil {
 		fmt.Printf("Error loading configuration: %v\n", err)
 		os.Exit(1)
 	}
@@ -50,46 +153,101 @@ func main() {
 		}
 
 	case "lookup":
-		if len(os.Args) < 3 {
-			fmt.Println("Usage: jt lookup <card-number>")
+		if err := handleLookup(os.Args[2:]); err != nil {
+			fmt.Printf("Error looking up issue: %v\n", err)
 			os.Exit(1)
 		}
-		if err := handleLookup(os.Args[2]); err != nil {
-			fmt.Printf("Error looking up issue: %v\n", err)
+
+	case "search":
+		if err := handleSearch(os.Args[2:]); err != nil {
+			fmt.Printf("Error searching issues: %v\n", err)
 			os.Exit(1)
 		}
 
-	case "branch":
-		if len(os.Args) < 4 {
-			fmt.Println("Usage: jt branch <card-number> <type>")
-			fmt.Println("Types: feature, bugfix, hotfix, release")
+	case "move":
+		if err := handleMove(os.Args[2:]); err != nil {
+			fmt.Printf("Error moving issue: %v\n", err)
 			os.Exit(1)
 		}
-		if err := handleBranch(os.Args[2], git.BranchType(os.Args[3])); err != nil {
+
+	case "branch":
+		if err := handleBranch(os.Args[2:]); err != nil {
 			fmt.Printf("Error creating branch: %v\n", err)
 			os.Exit(1)
 		}
 
 	case "commit":
-		if len(os.Args) < 3 {
-			fmt.Println("Usage: jt commit <card-number> [type]")
-			os.Exit(1)
-		}
-		commitType := "chore"
-		if len(os.Args) >= 4 {
-			commitType = os.Args[3]
-		}
-		if err := handleCommit(os.Args[2], commitType); err != nil {
+		if err := handleCommit(os.Args[2:]); err != nil {
 			fmt.Printf("Error committing changes: %v\n", err)
 			os.Exit(1)
 		}
 
 	case "push":
-		if err := git.PushBranch(); err != nil {
+		if err := handlePush(os.Args[2:]); err != nil {
 			fmt.Printf("Error pushing branch: %v\n", err)
 			os.Exit(1)
 		}
 
+	case "pr":
+		if err := handlePR(os.Args[2:]); err != nil {
+			fmt.Printf("Error creating pull request: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "finish":
+		if err := handleFinish(os.Args[2:]); err != nil {
+			fmt.Printf("Error finishing branch: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "release":
+		if err := handleRelease(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "changelog":
+		if err := handleChangelog(os.Args[2:]); err != nil {
+			fmt.Printf("Error generating changelog: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "comment":
+		if err := handleComment(os.Args[2:]); err != nil {
+			fmt.Printf("Error adding comment: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "comments":
+		if err := handleComments(os.Args[2:]); err != nil {
+			fmt.Printf("Error listing comments: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "auth":
+		if err := handleAuth(os.Args[2:]); err != nil {
+			fmt.Printf("Error managing credentials: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "hooks":
+		if err := handleHooks(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "config":
+		if err := handleConfig(os.Args[2:]); err != nil {
+			fmt.Printf("Error showing configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "time":
+		if err := handleTime(os.Args[2:]); err != nil {
+			fmt.Printf("Error tracking time: %v\n", err)
+			os.Exit(1)
+		}
+
 	default:
 		fmt.Printf("Unknown command: %s\n", os.Args[1])
 		printUsage()
@@ -97,8 +255,18 @@ func main() {
 	}
 }
 
-func handleLookup(issueKey string) error {
-	issue, err := jira.FetchIssue(issueKey)
+func handleLookup(args []string) error {
+	issueKey, _, err := issueKeyArg(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	issue, err := client.FetchIssue(issueKey)
 	if err != nil {
 		return err
 	}
@@ -107,31 +275,246 @@ func handleLookup(issueKey string) error {
 	return nil
 }
 
-func handleBranch(issueKey string, branchType git.BranchType) error {
-	issue, err := jira.FetchIssue(issueKey)
+func handleBranch(args []string) error {
+	fs := flag.NewFlagSet("branch", flag.ContinueOnError)
+	noTransition := fs.Bool("no-transition", false, "skip configured transition rules")
+
+	positional, err := parseArgs(fs, args)
 	if err != nil {
 		return err
 	}
+	if len(positional) < 2 {
+		return fmt.Errorf("usage: jt branch <card-number> <type> [--no-transition]\nTypes: feature, bugfix, hotfix, release")
+	}
+	issueKey, branchType := positional[0], git.BranchType(positional[1])
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	issue, err := client.FetchIssue(issueKey)
+	if err != nil {
+		return err
+	}
+
+	branchIssue := git.BranchIssue{
+		Key:       issueKey,
+		Summary:   issue.Fields.Summary,
+		IssueType: issue.Fields.IssueType.Name,
+		Assignee:  issue.Fields.Assignee.DisplayName,
+	}
+	if len(issue.Fields.Components) > 0 {
+		branchIssue.Component = issue.Fields.Components[0].Name
+	}
 
-	return git.CreateBranch(issueKey, issue.Fields.Summary, branchType)
+	if err := git.CreateBranch(branchIssue, branchType); err != nil {
+		return err
+	}
+
+	if !*noTransition {
+		runTransitionRules(client, config.EventBranch, issueKey, branchType)
+	}
+	return nil
 }
 
-func handleCommit(issueKey, commitType string) error {
-	issue, err := jira.FetchIssue(issueKey)
+func handlePush(args []string) error {
+	fs := flag.NewFlagSet("push", flag.ContinueOnError)
+	noTransition := fs.Bool("no-transition", false, "skip configured transition rules")
+	noLink := fs.Bool("no-link", false, "do not link the branch to the issue")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	if err := git.PushBranch(); err != nil {
+		return err
+	}
+	if *noTransition && *noLink {
+		return nil
+	}
+
+	branch, err := git.GetCurrentBranch()
 	if err != nil {
 		return err
 	}
+	issueKey := git.IssueKeyFromBranch(branch)
+	if issueKey == "" {
+		return nil
+	}
 
-	return git.CommitChanges(issueKey, commitType, issue.Fields.Summary)
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+	if !*noLink {
+		linkBranch(client, issueKey, branch)
+	}
+	if !*noTransition {
+		runTransitionRules(client, config.EventPush, issueKey, git.BranchTypeOf(branch))
+	}
+	return nil
+}
+
+func handleCommit(args []string) error {
+	fs := flag.NewFlagSet("commit", flag.ContinueOnError)
+	message := fs.String("m", "", "message available to the commit template as .Message")
+	edit := fs.Bool("edit", false, "open the editor with the rendered message before committing")
+	staged := fs.Bool("staged", false, "commit only what is already staged")
+	all := fs.Bool("all", false, "stage every change, including untracked files")
+	interactive := fs.Bool("interactive", false, "pick the files to stage")
+	paths := fs.String("paths", "", "comma separated paths to stage")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	branchConfig := loadProjectConfig()
+	mode := config.CommitModeStaged
+	if branchConfig != nil && branchConfig.CommitMode != "" {
+		mode = branchConfig.CommitMode
+	}
+	modes := 0
+	for flagMode, set := range map[string]bool{
+		config.CommitModeStaged: *staged,
+		config.CommitModeAll:    *all,
+		commitModeInteractive:   *interactive,
+		commitModePaths:         *paths != "",
+	} {
+		if set {
+			mode = flagMode
+			modes++
+		}
+	}
+	if modes > 1 {
+		return fmt.Errorf("--staged, --all, --interactive and --paths are mutually exclusive")
+	}
+
+	issueKey, rest, err := issueKeyArg(positional)
+	if err != nil {
+		return err
+	}
+	var commitTypes config.CommitTypesConfig
+	if branchConfig != nil {
+		commitTypes = branchConfig.CommitTypes
+	}
+	commitType := ""
+	if len(rest) > 0 {
+		commitType = rest[0]
+		if err := commitTypes.Validate(commitType); err != nil {
+			return err
+		}
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	issue, err := client.FetchIssue(issueKey)
+	if err != nil {
+		return err
+	}
+
+	if commitType == "" {
+		branch, err := git.GetCurrentBranch()
+		if err != nil {
+			return err
+		}
+		commitType = commitTypes.Infer(issue.Fields.IssueType.Name, string(git.BranchTypeOf(branch)))
+		if err := commitTypes.Validate(commitType); err != nil {
+			return fmt.Errorf("inferred %v, check commit_types in .jt-config.json", err)
+		}
+	}
+
+	// Stage only once Jira answered, so a failed lookup leaves the index as
+	// it was. The template lists the staged files.
+	if err := stageChanges(mode, splitList(*paths)); err != nil {
+		return err
+	}
+
+	data, err := commitTemplateData(client, issue, commitType, *message)
+	if err != nil {
+		return err
+	}
+	commitMessage, err := git.FormatCommitMessage(branchConfig, data)
+	if err != nil {
+		return err
+	}
+
+	return git.CommitChanges(commitMessage, *edit)
+}
+
+func commitTemplateData(client *jira.Client, issue *jira.JiraIssue, commitType, message string) (git.CommitTemplateData, error) {
+	data := git.CommitTemplateData{
+		Type:        commitType,
+		Key:         issue.Key,
+		Summary:     issue.Fields.Summary,
+		Message:     message,
+		URL:         client.BrowseURL(issue.Key),
+		IssueType:   issue.Fields.IssueType.Name,
+		Status:      issue.Fields.Status.Name,
+		Priority:    issue.Fields.Priority.Name,
+		Assignee:    issue.Fields.Assignee.DisplayName,
+		Description: issue.Fields.Description.Markdown(),
+		Labels:      issue.Fields.Labels,
+	}
+	for _, component := range issue.Fields.Components {
+		data.Components = append(data.Components, component.Name)
+	}
+
+	var err error
+	if data.Branch, err = git.GetCurrentBranch(); err != nil {
+		return data, err
+	}
+	if data.Files, err = git.GetStagedFiles(); err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// loadProjectConfig returns the configuration of the current project, or
+// nil when there is none.
+func loadProjectConfig() *config.BranchConfig {
+	projectRoot, err := git.GetProjectRoot()
+	if err != nil {
+		return nil
+	}
+	branchConfig, err := config.LoadProjectBranchConfig(projectRoot)
+	if err != nil {
+		return nil
+	}
+	return branchConfig
 }
 
 func printUsage() {
 	fmt.Println("Usage:")
+	fmt.Println("  jt [--profile name] <command>   - Run a command against a Jira profile")
+	fmt.Println("  jt [-c key=value] <command>     - Override a configuration setting for one command")
 	fmt.Println("  jt setup                        - Run setup wizard")
-	fmt.Println("  jt lookup <card-number>         - Look up Jira issue details")
-	fmt.Println("  jt branch <card-number> <type>  - Create branch from Jira issue")
-	fmt.Println("  jt commit <card-number> [type]  - Commit changes with Jira issue summary")
-	fmt.Println("  jt push                         - Push current branch to remote")
+	fmt.Println("  jt auth <login|logout|status|list> - Manage Jira credentials (login --store, --migrate)")
+	fmt.Println("  jt lookup [card-number]         - Look up Jira issue details")
+	fmt.Println("  jt search '<JQL>' [flags]       - Search issues (--limit, --fields, --json)")
+	fmt.Println("  jt move [card-number] <status>  - Transition issue to another status")
+	fmt.Println("  jt branch <card-number> <type>  - Create branch from Jira issue (--no-transition)")
+	fmt.Println("  jt commit [card-number] [type]  - Commit staged changes with Jira issue summary")
+	fmt.Println("                                    (-m, --edit, --staged, --all, --interactive, --paths)")
+	fmt.Println("  jt push                         - Push current branch to remote (--no-transition, --no-link)")
+	fmt.Println("  jt pr [card-number]             - Open a pull request for the current branch")
+	fmt.Println("                                    (--base, --title, --draft, --dry-run, --no-transition, --no-link)")
+	fmt.Println("  jt finish [branch]              - Merge a finished Git Flow branch and delete it")
+	fmt.Println("                                    (--no-ff, --ff, --squash, --tag, --no-tag, --keep, --push)")
+	fmt.Println("  jt release start <version|major|minor|patch> - Create a release branch and Jira fix version")
+	fmt.Println("  jt release finish [version]     - Finish the release and mark the Jira version released")
+	fmt.Println("  jt changelog [from..to]         - Generate release notes from commits and Jira")
+	fmt.Println("                                    (--group, --format, --output, --update)")
+	fmt.Println("  jt comment [card-number] [text] - Comment on an issue in Markdown, in $EDITOR without text")
+	fmt.Println("                                    (--from-commits, --edit, --dry-run)")
+	fmt.Println("  jt comments [card-number]       - List the comments on an issue")
+	fmt.Println("  jt time <start|stop|status|log|sync> - Track time and log work to Jira")
+	fmt.Println("  jt hooks <install|uninstall|status> - Manage commit-msg and prepare-commit-msg hooks")
+	fmt.Println("  jt config show [--origin]       - Show the merged configuration and where each value comes from")
+	fmt.Println("\nWhen [card-number] is omitted it is taken from the current branch name.")
 	fmt.Println("\nBranch types:")
 	fmt.Println("  feature  - New feature branch (from development)")
 	fmt.Println("  bugfix   - Bug fix branch (from development)")
@@ -145,6 +528,7 @@ func printUsage() {
 	fmt.Println("  style    - Code style")
 	fmt.Println("  refactor - Code refactoring")
 	fmt.Println("  test     - Testing")
+	fmt.Println("When omitted, the commit type is inferred from the issue type and branch type.")
 }
 
 func printIssueDetails(issue *jira.JiraIssue) {
@@ -153,7 +537,11 @@ func printIssueDetails(issue *jira.JiraIssue) {
 	fmt.Printf("Summary: %s\n", issue.Fields.Summary)
 	fmt.Printf("Status: %s\n", issue.Fields.Status.Name)
 	fmt.Printf("Assignee: %s\n", issue.Fields.Assignee.DisplayName)
-	fmt.Printf("Description:\n%s\n", issue.Fields.Description)
+	if issue.Fields.Description.IsEmpty() {
+		fmt.Println("Description: (none)")
+		return
+	}
+	fmt.Printf("Description:\n%s\n", issue.Fields.Description.Terminal())
 }
 
 func runSetup() error {
@@ -162,47 +550,32 @@ func runSetup() error {
 		return fmt.Errorf("not a git repository: %v", err)
 	}
 
-	// Handle Jira credentials globally
-	configDir, err := config.GetConfigPath()
+	// Handle Jira credentials globally, per profile
+	configDir, err := config.GetProfilePath(activeProfile)
 	if err != nil {
 		return fmt.Errorf("failed to get config directory: %v", err)
 	}
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
 
 	// Only do Jira setup if credentials don't exist
 	envPath := filepath.Join(configDir, ".env")
 	var err2 error
+	var settings envSettings
 	if _, err2 = os.Stat(envPath); os.IsNotExist(err2) {
 		// Jira Configuration
 		fmt.Println("\n=== Jira Configuration ===")
-		domain := promptUser("Jira Domain (e.g., company.atlassian.net): ")
-		email := promptUser("Jira Email: ")
-		apiToken := promptUser("Jira API Token: ")
-
-		fmt.Println("\nValidating Jira credentials...")
-		if err2 = jira.ValidateCredentials(domain, email, apiToken); err2 != nil {
-			return fmt.Errorf("credential validation failed: %v", err2)
+		if settings, err2 = setupJira(); err2 != nil {
+			return err2
 		}
 
 		// Save Jira credentials globally
-		envContent := fmt.Sprintf("JIRA_DOMAIN=%s\nJIRA_EMAIL=%s\nJIRA_API_TOKEN=%s\n",
-			domain, email, apiToken)
-		if err2 = os.WriteFile(envPath, []byte(envContent), 0600); err2 != nil {
+		if err2 = os.WriteFile(envPath, []byte(settings.String()), 0600); err2 != nil {
 			return fmt.Errorf("failed to save credentials: %v", err2)
 		}
 	}
 
-	// Project-specific git configuration
-	branchConfig := &config.BranchConfig{
-		ProjectPath: projectRoot,
-	}
-
-	// Rest of the setup remains the same, but save to project-specific location
-	if err := config.SaveProjectBranchConfig(branchConfig); err != nil {
-		return fmt.Errorf("failed to save project configuration: %v", err)
-	}
-
-	fmt.Printf("\nProject configuration saved in: %s\n", filepath.Join(projectRoot, ".jt-config.json"))
-
 	fmt.Println("Welcome to Jira Tools (jt) Setup!")
 	fmt.Println("=================================")
 
@@ -212,28 +585,138 @@ func runSetup() error {
 	}
 
 	// 2. Jira Configuration
-	fmt.Println("\n=== Jira Configuration ===")
-	domain := promptUser("Jira Domain (e.g., company.atlassian.net): ")
-	email := promptUser("Jira Email: ")
-	apiToken := promptUser("Jira API Token: ")
-
-	fmt.Println("\nValidating Jira credentials...")
-	if err := jira.ValidateCredentials(domain, email, apiToken); err != nil {
-		return fmt.Errorf("credential validation failed: %v", err)
+	if settings == nil {
+		fmt.Println("\n=== Jira Configuration ===")
+		if settings, err = setupJira(); err != nil {
+			return err
+		}
 	}
 	fmt.Println("✓ Credentials validated successfully")
 
 	// 3. Git Branch Configuration
-	fmt.Println("\n=== Git Branch Configuration ===")
+	team, teamPath, err := config.TeamSettings(projectRoot)
+	if err != nil {
+		return err
+	}
+	// The local file only gets the settings asked here, so it does not
+	// override the team file for the others
+	local := make(map[string]interface{})
+	if activeProfile != config.DefaultProfile && team["profile"] != activeProfile {
+		local["profile"] = activeProfile
+	}
+
+	if teamBranchSettings(team) {
+		fmt.Printf("\nThe branches are configured in %s, skipping the repository type questions\n", filepath.Base(teamPath))
+	} else {
+		fmt.Println("\n=== Git Branch Configuration ===")
+		branchConfig, err := promptBranchConfig()
+		if err != nil {
+			return err
+		}
+		local["is_monorepo"] = branchConfig.IsMonorepo
+		local["development_branch"] = branchConfig.DevelopmentBranch
+		if branchConfig.ProductionBranch != "" {
+			local["production_branch"] = branchConfig.ProductionBranch
+		}
+	}
+
+	// 4. Save configurations
+	// Save Jira credentials
+	if err = os.WriteFile(envPath, []byte(settings.String()), 0600); err != nil {
+		return fmt.Errorf("failed to save credentials: %v", err)
+	}
+
+	// Save branch configuration
+	if err = config.SaveProjectSettings(projectRoot, local); err != nil {
+		return fmt.Errorf("failed to save branch configuration: %v", err)
+	}
+	resolution, err := config.Resolve(projectRoot, config.ResolveOptions{})
+	if err != nil {
+		return err
+	}
+	branchConfig := resolution.Config
+	if err = config.RecordRepo(projectRoot); err != nil {
+		fmt.Printf("Warning: could not record the repository for 'jt auth list': %v\n", err)
+	}
+
+	// 5. Print configuration summary
+	fmt.Println("\nConfiguration Summary")
+	fmt.Println("=====================")
+	fmt.Printf("Jira Profile: %s\n", activeProfile)
+	fmt.Printf("Jira URL: %s\n", settings.Get("JIRA_DOMAIN"))
+	fmt.Printf("Authentication: %s\n", authMethodName(settings.Get("JIRA_AUTH")))
+	if email := settings.Get("JIRA_EMAIL"); email != "" {
+		fmt.Printf("Jira User: %s\n", email)
+	}
+	fmt.Println("\nGit Configuration:")
+	if branchConfig.IsMonorepo {
+		fmt.Printf("Repository Type: Git Flow\n")
+		fmt.Printf("Production Branch: %s\n", branchConfig.ProductionBranch)
+		fmt.Printf("Development Branch: %s\n", branchConfig.DevelopmentBranch)
+	} else {
+		fmt.Printf("Repository Type: Single Branch\n")
+		fmt.Printf("Development Branch: %s\n", branchConfig.DevelopmentBranch)
+	}
+
+	// 6. Print next steps
+	fmt.Printf("\nConfiguration saved in: %s\n", configDir)
+	fmt.Printf("Project configuration saved in: %s\n", filepath.Join(projectRoot, config.LocalConfigFile))
+
+	fmt.Println("\nNext Steps")
+	fmt.Println("==========")
+	if branchConfig.IsMonorepo {
+		fmt.Println("For features:")
+		fmt.Println("1. Create a feature branch:")
+		fmt.Printf("   jt branch PROJ-123 feature\n")
+		fmt.Println("\nFor bug fixes:")
+		fmt.Println("1. Create a bugfix branch:")
+		fmt.Printf("   jt branch PROJ-123 bugfix\n")
+		fmt.Println("\nFor hotfixes:")
+		fmt.Println("1. Create a hotfix branch:")
+		fmt.Printf("   jt branch PROJ-123 hotfix\n")
+	} else {
+		fmt.Println("1. Create a feature branch:")
+		fmt.Printf("   jt branch PROJ-123 feature\n")
+	}
+
+	fmt.Println("\nCommon commands:")
+	fmt.Println("2. Look up issue details:")
+	fmt.Println("   jt lookup PROJ-123")
+	fmt.Println("3. Create a commit:")
+	fmt.Println("   jt commit PROJ-123 feat")
+	fmt.Println("4. Push changes:")
+	fmt.Println("   jt push")
 
+	gitignorePath := filepath.Join(projectRoot, ".gitignore")
+	// The local file is personal, team conventions go in the committed .jt.yaml
+	if err := appendToGitignore(gitignorePath, config.LocalConfigFile); err != nil {
+		fmt.Printf("Warning: Could not add %s to .gitignore: %v\n", config.LocalConfigFile, err)
+	}
+
+	return nil
+}
+
+// teamBranchSettings reports whether the team configuration file sets the
+// repository type or its branches.
+func teamBranchSettings(team map[string]interface{}) bool {
+	for _, key := range []string{"is_monorepo", "production_branch", "development_branch"} {
+		if _, ok := team[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// promptBranchConfig asks for the repository type and its branches.
+func promptBranchConfig() (*config.BranchConfig, error) {
 	// Get available branches
 	branches, err := git.GetAvailableBranches()
 	if err != nil {
-		return fmt.Errorf("failed to get branches: %v", err)
+		return nil, fmt.Errorf("failed to get branches: %v", err)
 	}
 
 	if len(branches) == 0 {
-		return fmt.Errorf("no branches found in repository")
+		return nil, fmt.Errorf("no branches found in repository")
 	}
 
 	fmt.Println("\nAvailable branches:")
@@ -241,8 +724,8 @@ func runSetup() error {
 		fmt.Printf("%d. %s\n", i+1, branch)
 	}
 
-	// 4. Repository Type Configuration
-	branchConfig = &config.BranchConfig{}
+	// Repository Type Configuration
+	branchConfig := &config.BranchConfig{}
 
 	fmt.Println("\nRepository Setup Options:")
 	fmt.Println("1. Single Branch (development only)")
@@ -257,7 +740,10 @@ func runSetup() error {
 
 		// Production branch
 		for {
-			prodInput := promptUser("Production branch (enter number or name) [main/master]: ")
+			prodInput, err := promptInput("Production branch (enter number or name) [main/master]: ")
+			if err != nil {
+				return nil, fmt.Errorf("no branch selected: %v", err)
+			}
 			if prodInput == "" {
 				// Try main or master as default
 				if contains(branches, "main") {
@@ -276,7 +762,10 @@ func runSetup() error {
 
 		// Development branch
 		for {
-			devInput := promptUser("Development branch (enter number or name) [develop]: ")
+			devInput, err := promptInput("Development branch (enter number or name) [develop]: ")
+			if err != nil {
+				return nil, fmt.Errorf("no branch selected: %v", err)
+			}
 			if devInput == "" && contains(branches, "develop") {
 				branchConfig.DevelopmentBranch = "develop"
 				break
@@ -295,17 +784,19 @@ func runSetup() error {
 		if !contains(branches, branchConfig.DevelopmentBranch) {
 			createDev := promptUser(fmt.Sprintf("Development branch '%s' doesn't exist. Create it? (Y/n): ", branchConfig.DevelopmentBranch))
 			if createDev == "" || strings.ToLower(createDev) == "y" {
-				if err := git.CreateBranch(branchConfig.ProductionBranch, "create development branch", git.BranchType("develop")); err != nil {
-					return fmt.Errorf("failed to create development branch: %v", err)
+				if err := git.CreateBranchFrom(branchConfig.DevelopmentBranch, branchConfig.ProductionBranch); err != nil {
+					return nil, fmt.Errorf("failed to create development branch: %v", err)
 				}
-				fmt.Printf("Created development branch '%s' from '%s'\n", branchConfig.DevelopmentBranch, branchConfig.ProductionBranch)
 			}
 		}
 	} else {
 		// Single branch setup
 		fmt.Println("\n=== Single Branch Configuration ===")
 		for {
-			devInput := promptUser("Development branch (enter number or name): ")
+			devInput, err := promptInput("Development branch (enter number or name): ")
+			if err != nil {
+				return nil, fmt.Errorf("no branch selected: %v", err)
+			}
 			if branch := getBranchFromInput(devInput, branches); branch != "" {
 				branchConfig.DevelopmentBranch = branch
 				break
@@ -314,75 +805,7 @@ func runSetup() error {
 		}
 	}
 
-	// 5. Save configurations
-	configDir, err = config.GetConfigPath()
-	if err != nil {
-		return fmt.Errorf("failed to get config directory: %v", err)
-	}
-
-	// Save Jira credentials
-	envPath = filepath.Join(configDir, ".env")
-	envContent := fmt.Sprintf("JIRA_DOMAIN=%s\nJIRA_EMAIL=%s\nJIRA_API_TOKEN=%s\n",
-		domain, email, apiToken)
-
-	if err = os.WriteFile(envPath, []byte(envContent), 0600); err != nil {
-		return fmt.Errorf("failed to save credentials: %v", err)
-	}
-
-	// Save branch configuration
-	if err = config.SaveProjectBranchConfig(branchConfig); err != nil {
-		return fmt.Errorf("failed to save branch configuration: %v", err)
-	}
-
-	// 6. Print configuration summary
-	fmt.Println("\nConfiguration Summary")
-	fmt.Println("=====================")
-	fmt.Printf("Jira Domain: %s\n", domain)
-	fmt.Printf("Jira Email: %s\n", email)
-	fmt.Println("\nGit Configuration:")
-	if branchConfig.IsMonorepo {
-		fmt.Printf("Repository Type: Git Flow\n")
-		fmt.Printf("Production Branch: %s\n", branchConfig.ProductionBranch)
-		fmt.Printf("Development Branch: %s\n", branchConfig.DevelopmentBranch)
-	} else {
-		fmt.Printf("Repository Type: Single Branch\n")
-		fmt.Printf("Development Branch: %s\n", branchConfig.DevelopmentBranch)
-	}
-
-	// 7. Print next steps
-	fmt.Printf("\nConfiguration saved in: %s\n", configDir)
-
-	fmt.Println("\nNext Steps")
-	fmt.Println("==========")
-	if branchConfig.IsMonorepo {
-		fmt.Println("For features:")
-		fmt.Println("1. Create a feature branch:")
-		fmt.Printf("   jt branch PROJ-123 feature\n")
-		fmt.Println("\nFor bug fixes:")
-		fmt.Println("1. Create a bugfix branch:")
-		fmt.Printf("   jt branch PROJ-123 bugfix\n")
-		fmt.Println("\nFor hotfixes:")
-		fmt.Println("1. Create a hotfix branch:")
-		fmt.Printf("   jt branch PROJ-123 hotfix\n")
-	} else {
-		fmt.Println("1. Create a feature branch:")
-		fmt.Printf("   jt branch PROJ-123 feature\n")
-	}
-
-	fmt.Println("\nCommon commands:")
-	fmt.Println("2. Look up issue details:")
-	fmt.Println("   jt lookup PROJ-123")
-	fmt.Println("3. Create a commit:")
-	fmt.Println("   jt commit PROJ-123 feat")
-	fmt.Println("4. Push changes:")
-	fmt.Println("   jt push")
-
-	gitignorePath := filepath.Join(projectRoot, ".gitignore")
-	if err := appendToGitignore(gitignorePath, ".jt-config.json"); err != nil {
-		fmt.Printf("Warning: Could not add .jt-config.json to .gitignore: %v\n", err)
-	}
-
-	return nil
+	return branchConfig, nil
 }
 
 func appendToGitignore(gitignorePath, entry string) error {
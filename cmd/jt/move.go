@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"jira-tools/internal/jira"
+)
+
+type transitionOptions struct {
+	// Resolution is set on transitions that offer a resolution field.
+	Resolution string
+	// Interactive allows prompting for ambiguous matches and required fields.
+	Interactive bool
+}
+
+func handleMove(args []string) error {
+	fs := flag.NewFlagSet("move", flag.ContinueOnError)
+	resolution := fs.String("resolution", "", "resolution to set when the transition asks for one")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	issueKey, rest, err := issueKeyArg(positional)
+	if err != nil {
+		return err
+	}
+	if len(rest) < 1 {
+		return fmt.Errorf("usage: jt move [card-number] <status> [--resolution <name>]")
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	target := strings.Join(rest, " ")
+	return transitionIssue(client, issueKey, target, transitionOptions{
+		Resolution:  *resolution,
+		Interactive: true,
+	})
+}
+
+// transitionIssue moves an issue to the status named target using the
+// workflow transitions Jira offers for it.
+func transitionIssue(client *jira.Client, issueKey, target string, opts transitionOptions) error {
+	transitions, err := client.Transitions(issueKey)
+	if err != nil {
+		return fmt.Errorf("failed to fetch transitions: %v", err)
+	}
+
+	matches, exact := jira.MatchTransitions(transitions, target)
+	var transition jira.Transition
+	switch {
+	case len(matches) == 0:
+		issue, err := client.FetchIssue(issueKey)
+		if err == nil && strings.EqualFold(issue.Fields.Status.Name, target) {
+			fmt.Printf("%s is already in %s\n", issueKey, issue.Fields.Status.Name)
+			return nil
+		}
+		return fmt.Errorf("no transition to %q available for %s (available: %s)",
+			target, issueKey, strings.Join(transitionLabels(transitions), ", "))
+	case len(matches) == 1 && exact:
+		transition = matches[0]
+	case !opts.Interactive && exact:
+		return fmt.Errorf("%q matches several transitions for %s: %s",
+			target, issueKey, strings.Join(transitionLabels(matches), ", "))
+	case !opts.Interactive:
+		return fmt.Errorf("no transition named %q for %s, did you mean %s?",
+			target, issueKey, strings.Join(transitionLabels(matches), ", "))
+	case len(matches) == 1:
+		// A partial match such as "Not Done" for "done" needs confirmation
+		answer := promptUser(fmt.Sprintf("No transition named %q, move %s to %s? (y/N): ",
+			target, issueKey, transitionLabels(matches)[0]))
+		if strings.ToLower(answer) != "y" {
+			return fmt.Errorf("cancelled")
+		}
+		transition = matches[0]
+	default:
+		fmt.Printf("Several transitions match %q:\n", target)
+		index, err := chooseOption(transitionLabels(matches))
+		if err != nil {
+			return err
+		}
+		transition = matches[index]
+	}
+
+	fields, err := transitionFields(transition, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DoTransition(issueKey, transition.ID, fields); err != nil {
+		return fmt.Errorf("failed to transition %s: %v", issueKey, err)
+	}
+
+	fmt.Printf("Moved %s to %s\n", issueKey, transition.To.Name)
+	return nil
+}
+
+// transitionFields collects values for the fields a transition screen
+// requires, such as resolution.
+func transitionFields(transition jira.Transition, opts transitionOptions) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+
+	ids := make([]string, 0, len(transition.Fields))
+	for id := range transition.Fields {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		field := transition.Fields[id]
+		if id == "resolution" && opts.Resolution != "" {
+			fields[id] = map[string]string{"name": opts.Resolution}
+			continue
+		}
+		if !field.Required || field.HasDefaultValue {
+			continue
+		}
+		if !opts.Interactive {
+			return nil, fmt.Errorf("transition %q requires field %q", transition.Name, field.Name)
+		}
+
+		if len(field.AllowedValues) > 0 {
+			labels := make([]string, len(field.AllowedValues))
+			for i, v := range field.AllowedValues {
+				labels[i] = v.Label()
+			}
+			fmt.Printf("%s is required:\n", field.Name)
+			index, err := chooseOption(labels)
+			if err != nil {
+				return nil, err
+			}
+			fields[id] = map[string]string{"id": field.AllowedValues[index].ID}
+			continue
+		}
+
+		value := promptUser(fmt.Sprintf("%s: ", field.Name))
+		if value == "" {
+			return nil, fmt.Errorf("field %q is required", field.Name)
+		}
+		fields[id] = value
+	}
+
+	return fields, nil
+}
+
+func transitionLabels(transitions []jira.Transition) []string {
+	labels := make([]string, len(transitions))
+	for i, t := range transitions {
+		if strings.EqualFold(t.Name, t.To.Name) {
+			labels[i] = t.To.Name
+		} else {
+			labels[i] = fmt.Sprintf("%s (%s)", t.To.Name, t.Name)
+		}
+	}
+	return labels
+}
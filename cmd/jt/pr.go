@@ -0,0 +1,171 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/git"
+	"jira-tools/internal/hosting"
+)
+
+// tokenEnv is the default environment variable of each provider's token.
+var tokenEnv = map[string]string{
+	hosting.GitHub:    "GITHUB_TOKEN",
+	hosting.GitLab:    "GITLAB_TOKEN",
+	hosting.Bitbucket: "BITBUCKET_TOKEN",
+}
+
+func handlePR(args []string) error {
+	fs := flag.NewFlagSet("pr", flag.ContinueOnError)
+	base := fs.String("base", "", "branch to merge into (default from the branch type)")
+	title := fs.String("title", "", "pull request title")
+	draft := fs.Bool("draft", false, "open the pull request as a draft")
+	dryRun := fs.Bool("dry-run", false, "print the pull request instead of creating it")
+	noTransition := fs.Bool("no-transition", false, "skip configured transition rules")
+	noLink := fs.Bool("no-link", false, "do not link the pull request to the issue")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	issueKey, rest, err := issueKeyArg(positional)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("usage: jt pr [card-number] [--base <branch>] [--title <title>] [--draft] [--dry-run]")
+	}
+
+	branchConfig := loadProjectConfig()
+	if branchConfig == nil {
+		return fmt.Errorf("no project configuration found, run 'jt setup' first")
+	}
+	branch, err := git.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+	branchType := git.BranchTypeOf(branch)
+	if *base == "" {
+		*base = pullRequestBase(branchConfig, branchType)
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+	issue, err := client.FetchIssue(issueKey)
+	if err != nil {
+		return err
+	}
+
+	data := hosting.TemplateData{
+		Key:         issue.Key,
+		Summary:     issue.Fields.Summary,
+		Description: issue.Fields.Description.Markdown(),
+		URL:         client.BrowseURL(issue.Key),
+		IssueType:   issue.Fields.IssueType.Name,
+		Status:      issue.Fields.Status.Name,
+		Branch:      branch,
+		Base:        *base,
+	}
+	commits, err := git.Log(git.BaseRef(*base), branch)
+	if err != nil {
+		return err
+	}
+	for i := len(commits) - 1; i >= 0; i-- {
+		data.Commits = append(data.Commits, commits[i].Subject)
+	}
+
+	pr := hosting.PullRequest{Head: branch, Base: *base, Draft: *draft || branchConfig.PullRequest.Draft}
+	if pr.Title, pr.Body, err = hosting.FormatPullRequest(branchConfig.PullRequest, data); err != nil {
+		return err
+	}
+	if *title != "" {
+		pr.Title = *title
+	}
+
+	if *dryRun {
+		fmt.Printf("%s -> %s\nTitle: %s\n\n%s", pr.Head, pr.Base, pr.Title, pr.Body)
+		return nil
+	}
+
+	provider, kind, err := newHostingProvider(branchConfig.PullRequest)
+	if err != nil {
+		return err
+	}
+	if !git.RemoteBranchExists(branch) {
+		if err := git.PushBranch(); err != nil {
+			return err
+		}
+	}
+
+	created, err := provider.CreatePullRequest(pr)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", provider.Name(), err)
+	}
+	fmt.Printf("Created %s #%d: %s\n", provider.Name(), created.Number, created.URL)
+
+	if !*noLink {
+		linkPullRequest(client, issueKey, kind, created, pr.Title)
+	}
+	if !*noTransition {
+		runTransitionRules(client, config.EventPR, issueKey, branchType)
+	}
+	return nil
+}
+
+// pullRequestBase returns the branch a pull request of the given branch
+// type targets: the first branch `jt finish` would merge it into.
+func pullRequestBase(branchConfig *config.BranchConfig, branchType git.BranchType) string {
+	targets, err := git.FinishTargets(branchConfig, branchType)
+	if err != nil {
+		return branchConfig.DevelopmentBranch
+	}
+	return targets[0]
+}
+
+// newHostingProvider configures the provider of origin, or the one set in
+// the project configuration, and returns it with its kind.
+func newHostingProvider(cfg config.PullRequestConfig) (hosting.Provider, string, error) {
+	providerCfg := hosting.Config{
+		Kind:       cfg.Provider,
+		APIURL:     cfg.APIURL,
+		Repository: cfg.Repository,
+		Username:   os.Getenv("BITBUCKET_USERNAME"),
+	}
+
+	// The host of origin also locates self-hosted APIs without an api_url
+	configured := providerCfg.Kind != "" && providerCfg.Repository != ""
+	if remote, err := git.GetRemote("origin"); err != nil {
+		if !configured {
+			return nil, "", err
+		}
+	} else {
+		// Unless origin is on another service than the configured one
+		if detected := hosting.DetectKind(remote.Host); providerCfg.Kind == "" || detected == "" || detected == providerCfg.Kind {
+			providerCfg.Host = remote.Host
+		}
+		if providerCfg.Repository == "" {
+			providerCfg.Repository = remote.Path
+		}
+		if providerCfg.Kind == "" {
+			providerCfg.Kind = hosting.DetectKind(remote.Host)
+		}
+		if providerCfg.Kind == "" {
+			return nil, "", fmt.Errorf("cannot tell the hosting service of %s, set pull_request.provider in .jt-config.json", remote.Host)
+		}
+	}
+
+	envName := cfg.TokenEnv
+	if envName == "" {
+		envName = tokenEnv[providerCfg.Kind]
+	}
+	providerCfg.Token = os.Getenv(envName)
+	if providerCfg.Token == "" && envName != "" {
+		return nil, "", fmt.Errorf("%s is not set, add it to ~/.jira-tools/.env or the environment", envName)
+	}
+	provider, err := hosting.NewProvider(providerCfg)
+	return provider, providerCfg.Kind, err
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/git"
+	"github.com/joho/godotenv"
+)
+
+// activeProfile is the Jira profile the command runs against, see
+// selectProfile.
+var activeProfile = config.DefaultProfile
+
+// selectProfile picks the Jira profile from the configuration layers: the
+// --profile flag, JT_PROFILE, the project files or ~/.jira-tools/config.
+// It also reports problems in the configuration files once per command.
+func selectProfile() (string, error) {
+	projectRoot, err := git.GetProjectRoot()
+	if err != nil {
+		projectRoot = ""
+	}
+	resolution, err := config.Resolve(projectRoot, config.Overrides)
+	if err != nil {
+		return "", err
+	}
+	// Every command runs this, keep stdout clean for the ones whose output
+	// is parsed or redirected
+	for _, warning := range resolution.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if resolution.HasProjectFile() {
+		if err := config.RecordRepo(projectRoot); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not record the repository for 'jt auth list': %v\n", err)
+		}
+	}
+
+	profile := resolution.Config.ProjectProfile()
+	if profile != config.DefaultProfile {
+		if err := config.ValidateProfileName(profile); err != nil {
+			return "", err
+		}
+	}
+	return profile, nil
+}
+
+// profileFlag returns the flag that selects the active profile, for hints
+// that name commands.
+func profileFlag() string {
+	if activeProfile == config.DefaultProfile {
+		return ""
+	}
+	return "--profile " + activeProfile + " "
+}
+
+// authList shows the Jira profiles and the repositories using them.
+func authList() error {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		fmt.Println("No profiles, run 'jt setup' first")
+		return nil
+	}
+
+	repos, err := config.LoadKnownRepos()
+	if err != nil {
+		return err
+	}
+	reposByProfile := make(map[string][]string)
+	for _, repo := range repos {
+		// The project may have been moved or switched profiles since. Only
+		// its files count, not the overrides of this command.
+		resolution, err := config.Resolve(repo, config.ResolveOptions{})
+		if err != nil || !resolution.HasProjectFile() {
+			continue
+		}
+		profile := resolution.Config.ProjectProfile()
+		reposByProfile[profile] = append(reposByProfile[profile], repo)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tPROFILE\tJIRA URL\tAUTH\tREPOSITORIES")
+	for _, profile := range profiles {
+		envPath, err := config.GetEnvPath(profile)
+		if err != nil {
+			return err
+		}
+		values, err := godotenv.Read(envPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", envPath, err)
+		}
+		marker := ""
+		if profile == activeProfile {
+			marker = "*"
+		}
+		method := values["JIRA_AUTH"]
+		if method == "" {
+			method = authBasic
+		}
+		profileRepos := reposByProfile[profile]
+		if len(profileRepos) == 0 {
+			profileRepos = []string{"-"}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, profile, values["JIRA_DOMAIN"], method, profileRepos[0])
+		for _, repo := range profileRepos[1:] {
+			fmt.Fprintf(w, "\t\t\t\t%s\n", repo)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var unknown []string
+	for profile := range reposByProfile {
+		if !contains(profiles, profile) {
+			unknown = append(unknown, profile)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		fmt.Printf("\nWarning: repositories use profiles that are not set up: %s\n", strings.Join(unknown, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/git"
+	"jira-tools/internal/jira"
+)
+
+func handleRelease(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: jt release <start|finish>")
+	}
+
+	branchConfig := loadProjectConfig()
+	if branchConfig == nil {
+		return fmt.Errorf("no project configuration found, run 'jt setup' first")
+	}
+
+	switch args[0] {
+	case "start":
+		return handleReleaseStart(branchConfig, args[1:])
+	case "finish":
+		return handleReleaseFinish(branchConfig, args[1:])
+	default:
+		return fmt.Errorf("unknown release command %q, expected start or finish", args[0])
+	}
+}
+
+func handleReleaseStart(branchConfig *config.BranchConfig, args []string) error {
+	fs := flag.NewFlagSet("release start", flag.ContinueOnError)
+	project := fs.String("project", branchConfig.Release.Project, "Jira project to create the version in")
+	noJira := fs.Bool("no-jira", false, "only create the branch")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: jt release start <version|major|minor|patch> [--project KEY] [--no-jira]")
+	}
+
+	prefix := branchConfig.Finish.GetTagPrefix()
+	latest, err := git.LatestVersionTag(prefix)
+	if err != nil {
+		return err
+	}
+	version, err := nextReleaseVersion(positional[0], latest)
+	if err != nil {
+		return err
+	}
+	if git.TagExists(prefix + version.String()) {
+		return fmt.Errorf("version %s is already tagged", version)
+	}
+
+	baseBranch, err := git.BaseBranch(branchConfig, git.ReleaseBranch)
+	if err != nil {
+		return err
+	}
+	if err := git.CreateBranchFrom(string(git.ReleaseBranch)+"/"+version.String(), baseBranch); err != nil {
+		return err
+	}
+	if *noJira {
+		return nil
+	}
+
+	var since string
+	if latest != nil {
+		since = latest.Name
+	}
+	issueKeys, err := releaseIssueKeys(since, "HEAD")
+	if err != nil {
+		return err
+	}
+	projects := releaseProjects(*project, issueKeys)
+	if len(projects) == 0 {
+		fmt.Println("No issues referenced since the last release, skipping Jira version (set --project to create it anyway)")
+		return nil
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+	attachFixVersion(client, version.String(), projects, issueKeys)
+	return nil
+}
+
+func handleReleaseFinish(branchConfig *config.BranchConfig, args []string) error {
+	fs := flag.NewFlagSet("release finish", flag.ContinueOnError)
+	flags := addFinishFlags(fs)
+	date := fs.String("date", "", "release date (YYYY-MM-DD, default today)")
+	project := fs.String("project", branchConfig.Release.Project, "Jira project of the version")
+	noJira := fs.Bool("no-jira", false, "do not mark the Jira version as released")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("usage: jt release finish [version] [--date YYYY-MM-DD] [--no-jira] [jt finish flags]")
+	}
+
+	releaseDate := time.Now()
+	if *date != "" {
+		if releaseDate, err = time.ParseInLocation(jira.ReleaseDateLayout, *date, time.Local); err != nil {
+			return fmt.Errorf("invalid --date %q, expected YYYY-MM-DD", *date)
+		}
+	}
+
+	branch := ""
+	if len(positional) == 1 {
+		branch = string(git.ReleaseBranch) + "/" + positional[0]
+	} else if branch, err = git.GetCurrentBranch(); err != nil {
+		return err
+	}
+	if git.BranchTypeOf(branch) != git.ReleaseBranch {
+		return fmt.Errorf("%s is not a release branch, pass the version to finish", branch)
+	}
+	version, err := git.ParseVersion(strings.TrimPrefix(branch, string(git.ReleaseBranch)+"/"))
+	if err != nil {
+		return err
+	}
+
+	// Collect the issues before the branch is merged and deleted
+	prefix := branchConfig.Finish.GetTagPrefix()
+	previous, err := git.PreviousVersionTag(prefix, version)
+	if err != nil {
+		return err
+	}
+	var since string
+	if previous != nil {
+		since = previous.Name
+	}
+
+	var issueKeys []string
+	switch {
+	case git.BranchExists(branch):
+		if issueKeys, err = releaseIssueKeys(since, branch); err != nil {
+			return err
+		}
+		if err := finishBranch(branchConfig, branch, flags); err != nil {
+			return err
+		}
+	case git.TagExists(prefix + version.String()):
+		// Already finished with git, only the Jira version is left
+		if issueKeys, err = releaseIssueKeys(since, prefix+version.String()); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("release branch %s does not exist", branch)
+	}
+
+	if *noJira {
+		return nil
+	}
+	projects := releaseProjects(*project, issueKeys)
+	if len(projects) == 0 {
+		fmt.Println("No issues referenced in this release, skipping Jira version (set --project to release it anyway)")
+		return nil
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+	for _, projectKey := range projects {
+		jiraVersion, _, err := client.EnsureVersion(projectKey, version.String())
+		if err != nil {
+			fmt.Printf("Warning: could not find version %s in %s: %v\n", version, projectKey, err)
+			continue
+		}
+		if jiraVersion.Released {
+			fmt.Printf("Jira version %s in %s is already released\n", version, projectKey)
+			continue
+		}
+		if err := client.ReleaseVersion(jiraVersion.ID, releaseDate); err != nil {
+			fmt.Printf("Warning: could not release version %s in %s: %v\n", version, projectKey, err)
+			continue
+		}
+		fmt.Printf("Released Jira version %s in %s on %s\n", version, projectKey, releaseDate.Format(jira.ReleaseDateLayout))
+	}
+	return nil
+}
+
+// nextReleaseVersion resolves the argument of `jt release start`: either an
+// explicit version or the part of the latest version to bump.
+func nextReleaseVersion(arg string, latest *git.VersionTag) (git.Version, error) {
+	var current git.Version
+	if latest != nil {
+		current = latest.Version
+	}
+
+	switch arg {
+	case "major", "minor", "patch":
+		return current.Bump(arg)
+	}
+
+	version, err := git.ParseVersion(arg)
+	if err != nil {
+		return git.Version{}, err
+	}
+	if latest != nil && !current.Less(version) {
+		return git.Version{}, fmt.Errorf("version %s is not newer than the latest release %s", version, latest.Name)
+	}
+	return version, nil
+}
+
+// releaseIssueKeys returns the issue keys referenced by the commits in
+// from..to.
+func releaseIssueKeys(from, to string) ([]string, error) {
+	commits, err := git.Log(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return git.IssueKeys(commits)
+}
+
+// releaseProjects returns the Jira projects that get the release version:
+// the configured project followed by the projects of the released issues.
+func releaseProjects(project string, issueKeys []string) []string {
+	var projects []string
+	seen := make(map[string]bool)
+	if project != "" {
+		projects = append(projects, project)
+		seen[project] = true
+	}
+	for _, issueKey := range issueKeys {
+		if projectKey := jira.ProjectKey(issueKey); !seen[projectKey] {
+			seen[projectKey] = true
+			projects = append(projects, projectKey)
+		}
+	}
+	return projects
+}
+
+// attachFixVersion creates or reuses the version in every project and adds
+// it to the fix versions of the issues. The release branch already exists at
+// this point, so failures are reported as warnings.
+func attachFixVersion(client *jira.Client, name string, projects, issueKeys []string) {
+	ready := make(map[string]bool)
+	for _, projectKey := range projects {
+		_, created, err := client.EnsureVersion(projectKey, name)
+		if err != nil {
+			fmt.Printf("Warning: could not create version %s in %s: %v\n", name, projectKey, err)
+			continue
+		}
+		if created {
+			fmt.Printf("Created Jira version %s in %s\n", name, projectKey)
+		} else {
+			fmt.Printf("Using existing Jira version %s in %s\n", name, projectKey)
+		}
+		ready[projectKey] = true
+	}
+
+	updated := 0
+	for _, issueKey := range issueKeys {
+		if !ready[jira.ProjectKey(issueKey)] {
+			continue
+		}
+		if err := client.AddFixVersion(issueKey, name); err != nil {
+			fmt.Printf("Warning: could not set fix version on %s: %v\n", issueKey, err)
+			continue
+		}
+		updated++
+	}
+	if len(issueKeys) > 0 {
+		fmt.Printf("Set fix version %s on %d of %d issue(s)\n", name, updated, len(issueKeys))
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"jira-tools/internal/git"
+)
+
+func TestNextReleaseVersion(t *testing.T) {
+	latest := &git.VersionTag{Name: "v1.4.2", Version: git.Version{Major: 1, Minor: 4, Patch: 2}}
+	tests := []struct {
+		name   string
+		arg    string
+		latest *git.VersionTag
+		want   string
+		errMsg string
+	}{
+		{name: "major", arg: "major", latest: latest, want: "2.0.0"},
+		{name: "minor", arg: "minor", latest: latest, want: "1.5.0"},
+		{name: "patch", arg: "patch", latest: latest, want: "1.4.3"},
+		{name: "first minor", arg: "minor", want: "0.1.0"},
+		{name: "explicit", arg: "1.5.0", latest: latest, want: "1.5.0"},
+		{name: "explicit with prefix", arg: "v2.0.0", latest: latest, want: "2.0.0"},
+		{name: "explicit first", arg: "0.9.0", want: "0.9.0"},
+		{name: "not newer", arg: "1.4.2", latest: latest, errMsg: "not newer than the latest release v1.4.2"},
+		{name: "older", arg: "1.3.9", latest: latest, errMsg: "not newer"},
+		{name: "prerelease", arg: "1.5.0-rc.1", latest: latest, errMsg: "invalid version"},
+		{name: "unknown part", arg: "build", latest: latest, errMsg: "invalid version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextReleaseVersion(tt.arg, tt.latest)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("nextReleaseVersion(%q) = %v, %v, want an error with %q", tt.arg, got, err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil || got.String() != tt.want {
+				t.Errorf("nextReleaseVersion(%q) = %v, %v, want %s", tt.arg, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"jira-tools/internal/jira"
+)
+
+const defaultSearchColumns = "key,type,status,assignee,summary"
+
+// searchColumn describes a selectable column of `jt search` output.
+type searchColumn struct {
+	// field is the Jira field requested from the API.
+	field string
+	value func(issue *jira.JiraIssue) string
+}
+
+var searchColumns = map[string]searchColumn{
+	"key":        {"key", func(i *jira.JiraIssue) string { return i.Key }},
+	"summary":    {"summary", func(i *jira.JiraIssue) string { return i.Fields.Summary }},
+	"status":     {"status", func(i *jira.JiraIssue) string { return i.Fields.Status.Name }},
+	"assignee":   {"assignee", func(i *jira.JiraIssue) string { return i.Fields.Assignee.DisplayName }},
+	"reporter":   {"reporter", func(i *jira.JiraIssue) string { return i.Fields.Reporter.DisplayName }},
+	"type":       {"issuetype", func(i *jira.JiraIssue) string { return i.Fields.IssueType.Name }},
+	"priority":   {"priority", func(i *jira.JiraIssue) string { return i.Fields.Priority.Name }},
+	"project":    {"project", func(i *jira.JiraIssue) string { return i.Fields.Project.Key }},
+	"labels":     {"labels", func(i *jira.JiraIssue) string { return strings.Join(i.Fields.Labels, ",") }},
+	"components": {"components", issueComponents},
+	"created":    {"created", func(i *jira.JiraIssue) string { return shortDate(i.Fields.Created) }},
+	"updated":    {"updated", func(i *jira.JiraIssue) string { return shortDate(i.Fields.Updated) }},
+}
+
+func handleSearch(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	limit := fs.Int("limit", 0, "maximum number of issues to return (0 = all)")
+	fields := fs.String("fields", defaultSearchColumns, "comma separated columns to display")
+	asJSON := fs.Bool("json", false, "print results as JSON")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: jt search '<JQL>' [--limit N] [--fields %s] [--json]", defaultSearchColumns)
+	}
+	jql := strings.Join(positional, " ")
+
+	columns := splitList(*fields)
+	var apiFields []string
+	for _, name := range columns {
+		column, ok := searchColumns[name]
+		if !ok {
+			return fmt.Errorf("unknown field %q (available: %s)", name, strings.Join(searchColumnNames(), ", "))
+		}
+		if column.field != "key" {
+			apiFields = append(apiFields, column.field)
+		}
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	issues, err := client.Search(jql, jira.SearchOptions{Fields: apiFields, Limit: *limit})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printSearchJSON(issues, columns)
+	}
+	printSearchTable(issues, columns)
+	return nil
+}
+
+func printSearchTable(issues []jira.JiraIssue, columns []string) {
+	if len(issues) == 0 {
+		fmt.Println("No issues found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, name := range columns {
+		header[i] = strings.ToUpper(name)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for i := range issues {
+		row := make([]string, len(columns))
+		for j, name := range columns {
+			row[j] = searchColumns[name].value(&issues[i])
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d issue(s)\n", len(issues))
+}
+
+func printSearchJSON(issues []jira.JiraIssue, columns []string) error {
+	rows := make([]map[string]string, 0, len(issues))
+	for i := range issues {
+		row := make(map[string]string, len(columns))
+		for _, name := range columns {
+			row[name] = searchColumns[name].value(&issues[i])
+		}
+		rows = append(rows, row)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+func searchColumnNames() []string {
+	return []string{"key", "summary", "status", "assignee", "reporter", "type",
+		"priority", "project", "labels", "components", "created", "updated"}
+}
+
+func issueComponents(issue *jira.JiraIssue) string {
+	names := make([]string, 0, len(issue.Fields.Components))
+	for _, component := range issue.Fields.Components {
+		names = append(names, component.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// shortDate trims a Jira timestamp (2024-01-31T10:00:00.000+0000) to its date.
+func shortDate(timestamp string) string {
+	if len(timestamp) >= 10 {
+		return timestamp[:10]
+	}
+	return timestamp
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/git"
+)
+
+// Staging modes besides config.CommitModeStaged and config.CommitModeAll
+// that are only available as flags.
+const (
+	commitModeInteractive = "interactive"
+	commitModePaths       = "paths"
+)
+
+// stageChanges stages files for `jt commit` according to mode and makes
+// sure there is something to commit.
+func stageChanges(mode string, paths []string) error {
+	switch mode {
+	case config.CommitModeStaged:
+	case config.CommitModeAll:
+		if err := git.StageAll(); err != nil {
+			return err
+		}
+	case commitModePaths:
+		if err := git.StagePathspecs(paths); err != nil {
+			return err
+		}
+	case commitModeInteractive:
+		if err := pickFilesToStage(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid commit mode %q, expected staged or all", mode)
+	}
+
+	staged, err := git.GetStagedFiles()
+	if err != nil {
+		return err
+	}
+	if len(staged) == 0 {
+		status, err := git.GetStatusSummary()
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("nothing staged to commit\n%s\n\nStage files with git add, or use --all, --paths or --interactive", status)
+	}
+	return nil
+}
+
+// pickFilesToStage shows modified and untracked files and lets the user
+// toggle which of them are staged.
+func pickFilesToStage() error {
+	changes, err := git.GetChangedFiles()
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	selected := make([]bool, len(changes))
+	for i, change := range changes {
+		selected[i] = change.Staged()
+	}
+
+	for {
+		fmt.Println("\nChanged files:")
+		for i, change := range changes {
+			mark := " "
+			if selected[i] {
+				mark = "x"
+			}
+			fmt.Printf("%3d. [%s] %c%c %-50s %s\n", i+1, mark, change.Index, change.WorkTree, change.Path, changeStats(change))
+		}
+
+		input := promptUser("Toggle files (e.g. 1 3-5, a = all, n = none, enter = done, q = abort): ")
+		switch strings.ToLower(input) {
+		case "":
+			var stage, unstage []string
+			for i, change := range changes {
+				if selected[i] && (!change.Staged() || change.WorkTree != ' ') {
+					stage = append(stage, change.Path)
+				}
+				if !selected[i] && change.Staged() {
+					unstage = append(unstage, change.Path)
+				}
+			}
+			if err := git.UnstagePaths(unstage); err != nil {
+				return err
+			}
+			return git.StagePaths(stage)
+		case "q":
+			return fmt.Errorf("aborted")
+		case "a", "n":
+			for i := range selected {
+				selected[i] = input == "a"
+			}
+		default:
+			indexes, err := parseSelection(input, len(changes))
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			for _, i := range indexes {
+				selected[i] = !selected[i]
+			}
+		}
+	}
+}
+
+func changeStats(change git.FileChange) string {
+	switch {
+	case change.Binary:
+		return "binary"
+	case change.Untracked():
+		return "new"
+	default:
+		return fmt.Sprintf("+%d -%d", change.Added, change.Deleted)
+	}
+}
+
+// parseSelection parses "1 3-5,7" into zero based indexes below max.
+func parseSelection(input string, max int) ([]int, error) {
+	var indexes []int
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+		bounds := strings.SplitN(field, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", field)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid selection %q", field)
+			}
+		}
+		if first < 1 || last > max || first > last {
+			return nil, fmt.Errorf("selection %q is out of range 1-%d", field, max)
+		}
+		for i := first; i <= last; i++ {
+			indexes = append(indexes, i-1)
+		}
+	}
+	return indexes, nil
+}
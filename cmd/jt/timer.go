@@ -0,0 +1,477 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"jira-tools/internal/config"
+	"jira-tools/internal/git"
+	"jira-tools/internal/jira"
+	"jira-tools/internal/timetrack"
+)
+
+const timeUsage = `Usage:
+  jt time start [card-number] [comment]          - Start a timer on an issue
+  jt time stop [--comment text] [--discard]      - Stop the timer and log the time to Jira
+  jt time status                                 - Show the running timer
+  jt time log [card-number] <duration> [comment] - Log time directly (e.g. 1h30m, "1w 2d 3h 4m")
+  jt time sync                                   - Retry worklogs that failed to post
+  jt time track <install|uninstall|status>       - Record branch checkouts with git hooks
+  jt time suggest [--from date] [--to date]      - Propose worklogs from recorded checkouts
+  jt time report [--from date] [--to date]       - Timesheet grid or export (--format csv|json|ics)`
+
+func handleTime(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand\n%s", timeUsage)
+	}
+
+	configDir, err := config.GetConfigPath()
+	if err != nil {
+		return err
+	}
+	store := timetrack.NewStore(configDir)
+
+	switch args[0] {
+	case "start":
+		return handleTimeStart(store, args[1:])
+	case "stop":
+		return handleTimeStop(store, args[1:])
+	case "status":
+		return handleTimeStatus(store)
+	case "log":
+		return handleTimeLog(args[1:])
+	case "sync":
+		return handleTimeSync(store)
+	case "track":
+		return handleTimeTrack(args[1:])
+	case "record":
+		return handleTimeRecord(store, args[1:])
+	case "suggest":
+		return handleTimeSuggest(store, args[1:])
+	case "report":
+		return handleTimeReport(store, args[1:])
+	default:
+		return fmt.Errorf("unknown subcommand %q\n%s", args[0], timeUsage)
+	}
+}
+
+func handleTimeStart(store *timetrack.Store, args []string) error {
+	issueKey, rest, err := issueKeyArg(args)
+	if err != nil {
+		return err
+	}
+
+	timer, err := store.Start(issueKey, strings.Join(rest, " "), time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Started timer for %s at %s\n", timer.IssueKey, timer.Started.Format("15:04"))
+	return nil
+}
+
+func handleTimeStop(store *timetrack.Store, args []string) error {
+	fs := flag.NewFlagSet("time stop", flag.ContinueOnError)
+	comment := fs.String("comment", "", "worklog comment")
+	discard := fs.Bool("discard", false, "stop the timer without logging time")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	worklog, err := store.Stop(time.Now())
+	if err != nil {
+		return err
+	}
+	if *comment != "" {
+		worklog.Comment = *comment
+	}
+
+	if *discard {
+		fmt.Printf("Discarded %s on %s\n", timetrack.FormatDuration(worklog.Duration), worklog.IssueKey)
+		return nil
+	}
+	if worklog.Duration < time.Minute {
+		fmt.Printf("Timer for %s ran for less than a minute, nothing logged\n", worklog.IssueKey)
+		return nil
+	}
+
+	client, err := newJiraClient()
+	if err == nil {
+		err = postWorklog(client, *worklog)
+	}
+	if err != nil {
+		if queueErr := store.AddPending(*worklog); queueErr != nil {
+			return fmt.Errorf("failed to log time (%v) and to queue it: %v", err, queueErr)
+		}
+		return fmt.Errorf("failed to log time, saved for later (run 'jt time sync'): %v", err)
+	}
+	return nil
+}
+
+func handleTimeStatus(store *timetrack.Store) error {
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	if state.Active == nil {
+		fmt.Println("No timer running")
+	} else {
+		fmt.Printf("Timer running for %s since %s (%s)\n",
+			state.Active.IssueKey,
+			state.Active.Started.Format("2006-01-02 15:04"),
+			timetrack.FormatDuration(time.Since(state.Active.Started)))
+	}
+
+	if len(state.Pending) > 0 {
+		fmt.Printf("%d worklog(s) waiting to be synced, run 'jt time sync'\n", len(state.Pending))
+	}
+	return nil
+}
+
+func handleTimeLog(args []string) error {
+	fs := flag.NewFlagSet("time log", flag.ContinueOnError)
+	started := fs.String("started", "", "start time as YYYY-MM-DD HH:MM (default: now minus duration)")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	issueKey, rest, err := issueKeyArg(positional)
+	if err != nil {
+		return err
+	}
+	if len(rest) < 1 {
+		return fmt.Errorf("usage: jt time log [card-number] <duration> [comment] [--started \"YYYY-MM-DD HH:MM\"]")
+	}
+
+	duration, err := timetrack.ParseDuration(rest[0])
+	if err != nil {
+		return err
+	}
+
+	worklog := timetrack.Worklog{
+		IssueKey: issueKey,
+		Started:  time.Now().Add(-duration),
+		Duration: duration,
+		Comment:  strings.Join(rest[1:], " "),
+	}
+	if *started != "" {
+		if worklog.Started, err = time.ParseInLocation("2006-01-02 15:04", *started, time.Local); err != nil {
+			return fmt.Errorf("invalid --started value: %v", err)
+		}
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+	return postWorklog(client, worklog)
+}
+
+func handleTimeSync(store *timetrack.Store) error {
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if len(state.Pending) == 0 {
+		fmt.Println("Nothing to sync")
+		return nil
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	synced := len(state.Pending)
+	var failed []timetrack.Worklog
+	for _, worklog := range state.Pending {
+		if err := postWorklog(client, worklog); err != nil {
+			fmt.Printf("Failed to log time on %s: %v\n", worklog.IssueKey, err)
+			failed = append(failed, worklog)
+		}
+	}
+
+	// Keep worklogs queued by other jt processes while we were syncing.
+	err = store.Update(func(state *timetrack.State) error {
+		if len(state.Pending) >= synced {
+			failed = append(failed, state.Pending[synced:]...)
+		}
+		state.Pending = failed
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d worklog(s) could not be synced", len(failed))
+	}
+	return nil
+}
+
+func postWorklog(client *jira.Client, worklog timetrack.Worklog) error {
+	if _, err := client.AddWorklog(worklog.IssueKey, worklog.Started, worklog.Duration, worklog.Comment); err != nil {
+		return err
+	}
+	fmt.Printf("Logged %s on %s\n", timetrack.FormatDuration(worklog.Duration), worklog.IssueKey)
+	return nil
+}
+
+// trackingHooks are the git hooks that feed the activity log used by
+// `jt time suggest`.
+var trackingHooks = map[string]string{
+	"post-checkout": "record checkout \"$@\"",
+	"post-commit":   "record commit",
+}
+
+func handleTimeTrack(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: jt time track <install|uninstall|status>")
+	}
+
+	names := make([]string, 0, len(trackingHooks))
+	for name := range trackingHooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch args[0] {
+		case "install":
+			body := git.HookBody(fmt.Sprintf("time %s >/dev/null 2>&1 || true", trackingHooks[name]))
+			if err := git.InstallHook(name, body); err != nil {
+				return err
+			}
+			fmt.Printf("Installed %s hook\n", name)
+		case "uninstall":
+			installed, err := git.HookInstalled(name)
+			if err != nil {
+				return err
+			}
+			if !installed {
+				continue
+			}
+			if err := git.UninstallHook(name); err != nil {
+				return err
+			}
+			fmt.Printf("Removed %s hook\n", name)
+		case "status":
+			installed, err := git.HookInstalled(name)
+			if err != nil {
+				return err
+			}
+			status := "not installed"
+			if installed {
+				status = "installed"
+			}
+			fmt.Printf("%s: %s\n", name, status)
+		default:
+			return fmt.Errorf("unknown action %q, expected install, uninstall or status", args[0])
+		}
+	}
+	return nil
+}
+
+// handleTimeRecord is called by the tracking hooks to log git activity.
+func handleTimeRecord(store *timetrack.Store, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: jt time record <checkout|commit> [hook arguments]")
+	}
+
+	kind := args[0]
+	switch kind {
+	case timetrack.EventCheckout:
+		// post-checkout receives <old-head> <new-head> <branch-flag>; file
+		// checkouts (flag 0) do not switch branches.
+		if len(args) >= 4 && args[3] != "1" {
+			return nil
+		}
+	case timetrack.EventCommit:
+	default:
+		return fmt.Errorf("unknown event %q", kind)
+	}
+
+	projectRoot, err := git.GetProjectRoot()
+	if err != nil {
+		return err
+	}
+	branch, err := git.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+
+	return store.RecordEvent(timetrack.Event{
+		Time:     time.Now(),
+		Kind:     kind,
+		Repo:     projectRoot,
+		Branch:   branch,
+		IssueKey: git.IssueKeyFromBranch(branch),
+	})
+}
+
+func handleTimeSuggest(store *timetrack.Store, args []string) error {
+	today := time.Now().Format("2006-01-02")
+
+	fs := flag.NewFlagSet("time suggest", flag.ContinueOnError)
+	from := fs.String("from", today, "first day to suggest worklogs for (YYYY-MM-DD)")
+	to := fs.String("to", today, "last day to suggest worklogs for (YYYY-MM-DD)")
+	workHours := fs.String("work-hours", "09:00-17:00", "working hours, time outside is ignored")
+	idle := fs.String("idle", "2h", "stretch without git activity after which time counts as idle")
+	minimum := fs.String("min", "5m", "drop suggestions shorter than this")
+	yes := fs.Bool("yes", false, "submit without asking for review")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	opts, err := suggestOptions(*from, *to, *workHours, *idle, *minimum)
+	if err != nil {
+		return err
+	}
+
+	events, err := store.Events(opts.From, opts.To)
+	if err != nil {
+		return err
+	}
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	opts.Logged = state.Logged
+
+	suggestions, idleIntervals := timetrack.Suggest(events, opts)
+	if len(suggestions) == 0 {
+		fmt.Println("No unlogged activity found. Is tracking enabled? (jt time track status)")
+		return nil
+	}
+
+	fmt.Println("Suggested worklogs:")
+	for i, s := range suggestions {
+		fmt.Printf("%2d. %s  %-12s %s\n", i+1, s.Day.Format("Mon 2006-01-02"), s.IssueKey, timetrack.FormatDuration(s.Duration))
+	}
+	if len(idleIntervals) > 0 {
+		fmt.Println("\nIdle gaps left out:")
+		for _, interval := range idleIntervals {
+			fmt.Printf("    %s %s-%s  %-12s %s\n", interval.Start.Format("Mon 2006-01-02"),
+				interval.Start.Format("15:04"), interval.End.Format("15:04"), interval.IssueKey,
+				timetrack.FormatDuration(interval.End.Sub(interval.Start)))
+		}
+	}
+
+	if !*yes {
+		answer := strings.ToLower(promptUser("\nSubmit these worklogs? (y = yes, e = edit each, N = cancel): "))
+		switch answer {
+		case "y", "yes":
+		case "e", "edit":
+			suggestions = reviewSuggestions(suggestions)
+		default:
+			fmt.Println("Nothing submitted")
+			return nil
+		}
+	}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, s := range suggestions {
+		worklog := timetrack.Worklog{IssueKey: s.IssueKey, Started: s.Started, Duration: s.Duration}
+		if err := postWorklog(client, worklog); err != nil {
+			fmt.Printf("Failed to log time on %s: %v\n", s.IssueKey, err)
+			failed++
+			continue
+		}
+		logged := s.Range()
+		if err := store.Update(func(state *timetrack.State) error {
+			state.Logged = append(state.Logged, logged)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d worklog(s) could not be submitted", failed)
+	}
+	return nil
+}
+
+// reviewSuggestions lets the user adjust or skip each suggestion.
+func reviewSuggestions(suggestions []timetrack.Suggestion) []timetrack.Suggestion {
+	var reviewed []timetrack.Suggestion
+	for _, s := range suggestions {
+		for {
+			input := promptUser(fmt.Sprintf("%s %s [%s] (enter = keep, 0 = skip, or new duration): ",
+				s.Day.Format("2006-01-02"), s.IssueKey, timetrack.FormatDuration(s.Duration)))
+			if input == "" {
+				reviewed = append(reviewed, s)
+				break
+			}
+			if input == "0" {
+				break
+			}
+			duration, err := timetrack.ParseDuration(input)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			s.Duration = duration
+			reviewed = append(reviewed, s)
+			break
+		}
+	}
+	return reviewed
+}
+
+func suggestOptions(from, to, workHours, idle, minimum string) (timetrack.SuggestOptions, error) {
+	var opts timetrack.SuggestOptions
+
+	fromDay, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		return opts, fmt.Errorf("invalid --from date: %v", err)
+	}
+	toDay, err := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err != nil {
+		return opts, fmt.Errorf("invalid --to date: %v", err)
+	}
+	opts.From = fromDay
+	opts.To = toDay.AddDate(0, 0, 1)
+	if now := time.Now(); opts.To.After(now) {
+		opts.To = now
+	}
+
+	hours := strings.SplitN(workHours, "-", 2)
+	if len(hours) != 2 {
+		return opts, fmt.Errorf("invalid --work-hours %q, expected HH:MM-HH:MM", workHours)
+	}
+	if opts.WorkStart, err = clockOffset(hours[0]); err != nil {
+		return opts, err
+	}
+	if opts.WorkEnd, err = clockOffset(hours[1]); err != nil {
+		return opts, err
+	}
+	if opts.WorkEnd <= opts.WorkStart {
+		return opts, fmt.Errorf("invalid --work-hours %q, end must be after start", workHours)
+	}
+
+	if opts.IdleGap, err = timetrack.ParseDuration(idle); err != nil {
+		return opts, err
+	}
+	if opts.MinDuration, err = timetrack.ParseDuration(minimum); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// clockOffset converts HH:MM into an offset from midnight.
+func clockOffset(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
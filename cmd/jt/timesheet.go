@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"jira-tools/internal/jira"
+	"jira-tools/internal/timetrack"
+)
+
+func handleTimeReport(store *timetrack.Store, args []string) error {
+	now := time.Now()
+	weekStart := now.AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+
+	fs := flag.NewFlagSet("time report", flag.ContinueOnError)
+	from := fs.String("from", weekStart.Format("2006-01-02"), "first day of the period (YYYY-MM-DD, default: this Monday)")
+	to := fs.String("to", now.Format("2006-01-02"), "last day of the period (YYYY-MM-DD)")
+	user := fs.String("user", "me", "whose worklogs to report: me, an account ID or a username")
+	groupBy := fs.String("by", timetrack.GroupByIssue, "grid rows: issue or project")
+	format := fs.String("format", "table", "output format: table, csv, json or ics")
+	output := fs.String("output", "", "write to a file instead of stdout")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	if *groupBy != timetrack.GroupByIssue && *groupBy != timetrack.GroupByProject {
+		return fmt.Errorf("invalid --by %q, expected issue or project", *groupBy)
+	}
+
+	fromDay, err := time.ParseInLocation("2006-01-02", *from, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid --from date: %v", err)
+	}
+	toDay, err := time.ParseInLocation("2006-01-02", *to, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid --to date: %v", err)
+	}
+	report := &timetrack.Report{From: fromDay, To: toDay.AddDate(0, 0, 1)}
+
+	client, err := newJiraClient()
+	if err != nil {
+		return err
+	}
+	if err := addJiraWorklogs(client, report, *user); err != nil {
+		return err
+	}
+	if *user == "me" {
+		if err := addLocalWorklogs(store, report); err != nil {
+			return err
+		}
+	}
+	report.Sort()
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "table":
+		err = report.WriteGrid(w, *groupBy)
+	case "csv":
+		err = report.WriteCSV(w)
+	case "json":
+		err = report.WriteJSON(w)
+	case "ics":
+		err = report.WriteICS(w)
+	default:
+		return fmt.Errorf("unknown format %q, expected table, csv, json or ics", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		fmt.Printf("Wrote %d entries (%s) to %s\n", len(report.Entries), timetrack.FormatDuration(report.Total()), *output)
+	}
+	return nil
+}
+
+// addJiraWorklogs adds the worklogs user logged in Jira during the report
+// period.
+func addJiraWorklogs(client *jira.Client, report *timetrack.Report, user string) error {
+	author := "currentUser()"
+	var me *jira.User
+	if user == "me" {
+		var err error
+		if me, err = client.Myself(); err != nil {
+			return err
+		}
+	} else {
+		author = jqlQuote(user)
+	}
+
+	jql := fmt.Sprintf("worklogAuthor = %s AND worklogDate >= %s AND worklogDate <= %s ORDER BY key",
+		author, jqlQuote(report.From.Format("2006-01-02")),
+		jqlQuote(report.To.AddDate(0, 0, -1).Format("2006-01-02")))
+	issues, err := client.Search(jql, jira.SearchOptions{Fields: []string{"summary", "project"}})
+	if err != nil {
+		return fmt.Errorf("failed to search worklogs: %v", err)
+	}
+
+	for _, issue := range issues {
+		worklogs, err := client.IssueWorklogs(issue.Key)
+		if err != nil {
+			return fmt.Errorf("failed to fetch worklogs of %s: %v", issue.Key, err)
+		}
+		for _, worklog := range worklogs {
+			if !worklogBy(worklog.Author, user, me) {
+				continue
+			}
+			started, err := worklog.StartedTime()
+			if err != nil {
+				continue
+			}
+			report.Add(timetrack.Entry{
+				IssueKey: issue.Key,
+				Project:  issue.Fields.Project.Key,
+				Summary:  issue.Fields.Summary,
+				Author:   worklog.Author.DisplayName,
+				Started:  started.In(time.Local),
+				Duration: time.Duration(worklog.TimeSpentSeconds) * time.Second,
+				Comment:  worklog.Comment,
+				Synced:   true,
+			})
+		}
+	}
+	return nil
+}
+
+// addLocalWorklogs adds time that has not reached Jira yet: worklogs waiting
+// for `jt time sync` and the running timer.
+func addLocalWorklogs(store *timetrack.Store, report *timetrack.Report) error {
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	for _, worklog := range state.Pending {
+		report.Add(timetrack.Entry{
+			IssueKey: worklog.IssueKey,
+			Started:  worklog.Started,
+			Duration: worklog.Duration,
+			Comment:  worklog.Comment,
+		})
+	}
+	if state.Active != nil {
+		report.Add(timetrack.Entry{
+			IssueKey: state.Active.IssueKey,
+			Started:  state.Active.Started,
+			Duration: time.Since(state.Active.Started).Round(time.Minute),
+			Comment:  "(timer running)",
+		})
+	}
+	return nil
+}
+
+func worklogBy(author *jira.User, user string, me *jira.User) bool {
+	if author == nil {
+		return false
+	}
+	if me != nil {
+		return (me.AccountID != "" && author.AccountID == me.AccountID) ||
+			(me.Name != "" && author.Name == me.Name)
+	}
+	return author.AccountID == user || author.Name == user || strings.EqualFold(author.EmailAddress, user)
+}
+
+// jqlQuote quotes a value for use in a JQL query.
+func jqlQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -2,4 +2,9 @@ module jira-tools
 
 go 1.16
 
-require github.com/joho/godotenv v1.5.1
+require (
+	github.com/joho/godotenv v1.5.1
+	golang.org/x/crypto v0.0.0-20220525230936-793ad666bf5e
+	golang.org/x/text v0.3.8
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,126 @@
+package adf
+
+import (
+    "fmt"
+    "strconv"
+)
+
+// Node types.
+const (
+    TypeDoc         = "doc"
+    TypeParagraph   = "paragraph"
+    TypeText        = "text"
+    TypeHeading     = "heading"
+    TypeBulletList  = "bulletList"
+    TypeOrderedList = "orderedList"
+    TypeListItem    = "listItem"
+    TypeCodeBlock   = "codeBlock"
+    TypeBlockquote  = "blockquote"
+    TypeRule        = "rule"
+    TypeHardBreak   = "hardBreak"
+    TypeTable       = "table"
+    TypeTableRow    = "tableRow"
+    TypeTableHeader = "tableHeader"
+    TypeTableCell   = "tableCell"
+    TypePanel       = "panel"
+    TypeMention     = "mention"
+    TypeEmoji       = "emoji"
+    TypeInlineCard  = "inlineCard"
+    TypeBlockCard   = "blockCard"
+    TypeStatus      = "status"
+    TypeDate        = "date"
+    TypeTaskList    = "taskList"
+    TypeTaskItem    = "taskItem"
+    TypeExpand      = "expand"
+    TypeMediaSingle = "mediaSingle"
+    TypeMediaGroup  = "mediaGroup"
+    TypeMedia       = "media"
+)
+
+// Mark types.
+const (
+    MarkStrong = "strong"
+    MarkEm     = "em"
+    MarkCode   = "code"
+    MarkStrike = "strike"
+    MarkLink   = "link"
+)
+
+// inlineTypes are the node types that appear inside paragraphs.
+var inlineTypes = map[string]bool{
+    TypeText:       true,
+    TypeHardBreak:  true,
+    TypeMention:    true,
+    TypeEmoji:      true,
+    TypeInlineCard: true,
+    TypeStatus:     true,
+    TypeDate:       true,
+}
+
+// Node is a node of an Atlassian Document Format document, the rich text
+// format of version 3 of the Jira REST API. Text nodes carry Text and Marks;
+// other nodes carry Content.
+type Node struct {
+    Type    string                 `json:"type"`
+    Version int                    `json:"version,omitempty"`
+    Attrs   map[string]interface{} `json:"attrs,omitempty"`
+    Content []*Node                `json:"content,omitempty"`
+    Text    string                 `json:"text,omitempty"`
+    Marks   []Mark                 `json:"marks,omitempty"`
+}
+
+// attr returns an attribute as a string. Numbers decoded from JSON are
+// float64 and are formatted without a fraction.
+func (n *Node) attr(name string) string {
+    return attrString(n.Attrs, name)
+}
+
+// intAttr returns a numeric attribute, or def when it is missing.
+func (n *Node) intAttr(name string, def int) int {
+    switch value := n.Attrs[name].(type) {
+    case int:
+        return value
+    case float64:
+        return int(value)
+    }
+    return def
+}
+
+// Mark formats a text node.
+type Mark struct {
+    Type  string                 `json:"type"`
+    Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// Doc returns a document with the given top-level blocks.
+func Doc(content ...*Node) *Node {
+    return &Node{Type: TypeDoc, Version: 1, Content: content}
+}
+
+// Paragraph returns a paragraph of inline nodes.
+func Paragraph(content ...*Node) *Node {
+    return &Node{Type: TypeParagraph, Content: content}
+}
+
+// Text returns a text node with optional marks.
+func Text(text string, marks ...Mark) *Node {
+    return &Node{Type: TypeText, Text: text, Marks: marks}
+}
+
+// Link returns a link mark.
+func Link(href string) Mark {
+    return Mark{Type: MarkLink, Attrs: map[string]interface{}{"href": href}}
+}
+
+func attrString(attrs map[string]interface{}, name string) string {
+    switch value := attrs[name].(type) {
+    case nil:
+        return ""
+    case string:
+        return value
+    case float64:
+        return strconv.FormatFloat(value, 'f', -1, 64)
+    default:
+        return fmt.Sprint(value)
+    }
+}
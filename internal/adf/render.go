@@ -0,0 +1,433 @@
+package adf
+
+import (
+    "strconv"
+    "strings"
+    "time"
+    "unicode/utf8"
+)
+
+type style int
+
+const (
+    terminalStyle style = iota
+    markdownStyle
+)
+
+// Terminal renders a document as plain text for the terminal: headings are
+// underlined, tables aligned, panels framed, and links shown after their text.
+func Terminal(doc *Node) string {
+    return render(doc, terminalStyle)
+}
+
+// Markdown renders a document as GitHub flavored Markdown.
+func Markdown(doc *Node) string {
+    return render(doc, markdownStyle)
+}
+
+func render(doc *Node, s style) string {
+    if doc == nil {
+        return ""
+    }
+    r := renderer{style: s}
+    nodes := doc.Content
+    if doc.Type != TypeDoc {
+        nodes = []*Node{doc}
+    }
+    return strings.Join(r.blocks(nodes, true), "\n")
+}
+
+type renderer struct {
+    style style
+}
+
+// blocks renders block nodes to lines, separated by blank lines when loose
+// is set and directly following each other otherwise, as in list items.
+func (r renderer) blocks(nodes []*Node, loose bool) []string {
+    var lines []string
+    for i := 0; i < len(nodes); i++ {
+        var block []string
+        if inlineTypes[nodes[i].Type] {
+            // Stray inline nodes form a paragraph of their own
+            j := i
+            for j < len(nodes) && inlineTypes[nodes[j].Type] {
+                j++
+            }
+            block = r.paragraph(nodes[i:j])
+            i = j - 1
+        } else {
+            block = r.block(nodes[i])
+        }
+
+        if len(block) == 0 {
+            continue
+        }
+        if loose && len(lines) > 0 {
+            lines = append(lines, "")
+        }
+        lines = append(lines, block...)
+    }
+    return lines
+}
+
+func (r renderer) block(n *Node) []string {
+    switch n.Type {
+    case TypeParagraph:
+        return r.paragraph(n.Content)
+
+    case TypeHeading:
+        text := strings.ReplaceAll(r.inline(n.Content), "\n", " ")
+        level := n.intAttr("level", 1)
+        if r.style == markdownStyle {
+            return []string{strings.Repeat("#", level) + " " + text}
+        }
+        underline := "-"
+        if level == 1 {
+            underline = "="
+        }
+        return []string{text, strings.Repeat(underline, utf8.RuneCountInString(text))}
+
+    case TypeBulletList, TypeOrderedList, TypeTaskList:
+        return r.list(n)
+
+    case TypeCodeBlock:
+        code := strings.Split(strings.TrimRight(plainText(n), "\n"), "\n")
+        if r.style == markdownStyle {
+            lines := []string{"```" + n.attr("language")}
+            lines = append(lines, code...)
+            return append(lines, "```")
+        }
+        return indent(code, "    ", "    ")
+
+    case TypeBlockquote:
+        return indent(r.blocks(n.Content, true), "> ", ">")
+
+    case TypePanel:
+        label := panelLabel(n.attr("panelType"))
+        if r.style == markdownStyle {
+            lines := append([]string{"**" + label + ":**"}, r.blocks(n.Content, true)...)
+            return indent(lines, "> ", ">")
+        }
+        lines := append([]string{label}, r.blocks(n.Content, true)...)
+        return indent(lines, "│ ", "│")
+
+    case TypeRule:
+        if r.style == markdownStyle {
+            return []string{"---"}
+        }
+        return []string{strings.Repeat("─", 40)}
+
+    case TypeTable:
+        return r.table(n)
+
+    case TypeExpand:
+        title := n.attr("title")
+        if r.style == markdownStyle && title != "" {
+            title = "**" + title + "**"
+        }
+        lines := r.blocks(n.Content, true)
+        if title == "" {
+            return lines
+        }
+        return append([]string{title}, lines...)
+
+    case TypeMediaSingle, TypeMediaGroup:
+        var lines []string
+        for _, media := range n.Content {
+            lines = append(lines, r.media(media))
+        }
+        return lines
+
+    case TypeMedia:
+        return []string{r.media(n)}
+
+    case TypeBlockCard:
+        return []string{r.card(n.attr("url"))}
+
+    default:
+        // Unknown containers keep their content
+        return r.blocks(n.Content, true)
+    }
+}
+
+func (r renderer) paragraph(nodes []*Node) []string {
+    text := r.inline(nodes)
+    if strings.TrimSpace(text) == "" {
+        return nil
+    }
+    return strings.Split(text, "\n")
+}
+
+func (r renderer) list(n *Node) []string {
+    start := n.intAttr("order", 1)
+    var lines []string
+    for i, item := range n.Content {
+        if item.Type == TypeTaskList || item.Type == TypeBulletList || item.Type == TypeOrderedList {
+            // Nested task lists are siblings of their parent item
+            lines = append(lines, indent(r.list(item), "  ", "")...)
+            continue
+        }
+
+        var marker string
+        var body []string
+        switch n.Type {
+        case TypeOrderedList:
+            marker = strconv.Itoa(start+i) + ". "
+        case TypeTaskList:
+            marker = "[ ] "
+            if item.attr("state") == "DONE" {
+                marker = "[x] "
+            }
+            if r.style == markdownStyle {
+                marker = "- " + marker
+            }
+        default:
+            marker = "• "
+            if r.style == markdownStyle {
+                marker = "- "
+            }
+        }
+
+        if item.Type == TypeTaskItem {
+            body = r.paragraph(item.Content)
+        } else {
+            body = r.blocks(item.Content, false)
+        }
+        if len(body) == 0 {
+            body = []string{""}
+        }
+        pad := strings.Repeat(" ", utf8.RuneCountInString(marker))
+        lines = append(lines, marker+body[0])
+        lines = append(lines, indent(body[1:], pad, "")...)
+    }
+    return lines
+}
+
+// table renders rows as a Markdown pipe table, or as aligned columns with
+// a rule under the header row.
+func (r renderer) table(n *Node) []string {
+    var rows [][]string
+    header := false
+    columns := 0
+    for i, row := range n.Content {
+        var cells []string
+        for _, cell := range row.Content {
+            text := strings.Join(r.blocks(cell.Content, false), " ")
+            if r.style == markdownStyle {
+                text = strings.ReplaceAll(text, "|", "\\|")
+            }
+            cells = append(cells, text)
+            if i == 0 && cell.Type == TypeTableHeader {
+                header = true
+            }
+        }
+        if len(cells) > columns {
+            columns = len(cells)
+        }
+        rows = append(rows, cells)
+    }
+    if len(rows) == 0 {
+        return nil
+    }
+
+    widths := make([]int, columns)
+    for i := range rows {
+        for len(rows[i]) < columns {
+            rows[i] = append(rows[i], "")
+        }
+        for j, cell := range rows[i] {
+            if w := utf8.RuneCountInString(cell); w > widths[j] {
+                widths[j] = w
+            }
+        }
+    }
+    pad := func(cell string, width int) string {
+        return cell + strings.Repeat(" ", width-utf8.RuneCountInString(cell))
+    }
+
+    var lines []string
+    if r.style == markdownStyle {
+        if !header {
+            // Markdown tables need a header row
+            rows = append([][]string{make([]string, columns)}, rows...)
+        }
+        // The delimiter row needs three dashes, keep the cells aligned with it
+        for j := range widths {
+            if widths[j] < 3 {
+                widths[j] = 3
+            }
+        }
+        for i, row := range rows {
+            cells := make([]string, columns)
+            for j, cell := range row {
+                cells[j] = pad(cell, widths[j])
+            }
+            lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+            if i == 0 {
+                for j := range cells {
+                    cells[j] = strings.Repeat("-", widths[j])
+                }
+                lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+            }
+        }
+        return lines
+    }
+
+    for i, row := range rows {
+        cells := make([]string, columns)
+        for j, cell := range row {
+            cells[j] = pad(cell, widths[j])
+        }
+        lines = append(lines, strings.TrimRight(strings.Join(cells, "  "), " "))
+        if i == 0 && header {
+            for j := range cells {
+                cells[j] = strings.Repeat("─", widths[j])
+            }
+            lines = append(lines, strings.Join(cells, "  "))
+        }
+    }
+    return lines
+}
+
+func (r renderer) media(n *Node) string {
+    name := n.attr("alt")
+    if name == "" {
+        name = n.attr("id")
+    }
+    return "[attachment: " + name + "]"
+}
+
+func (r renderer) card(url string) string {
+    if r.style == markdownStyle {
+        return "<" + url + ">"
+    }
+    return url
+}
+
+// inline renders inline nodes; hard breaks become newlines.
+func (r renderer) inline(nodes []*Node) string {
+    var b strings.Builder
+    for _, n := range nodes {
+        switch n.Type {
+        case TypeText:
+            b.WriteString(r.text(n))
+        case TypeHardBreak:
+            if r.style == markdownStyle {
+                b.WriteString("\\")
+            }
+            b.WriteString("\n")
+        case TypeMention:
+            text := n.attr("text")
+            if text == "" {
+                text = n.attr("id")
+            }
+            if !strings.HasPrefix(text, "@") {
+                text = "@" + text
+            }
+            b.WriteString(text)
+        case TypeEmoji:
+            if text := n.attr("text"); text != "" {
+                b.WriteString(text)
+            } else {
+                b.WriteString(n.attr("shortName"))
+            }
+        case TypeInlineCard:
+            b.WriteString(r.card(n.attr("url")))
+        case TypeStatus:
+            b.WriteString("[" + strings.ToUpper(n.attr("text")) + "]")
+        case TypeDate:
+            b.WriteString(formatTimestamp(n.attr("timestamp")))
+        default:
+            b.WriteString(n.Text)
+            b.WriteString(r.inline(n.Content))
+        }
+    }
+    return b.String()
+}
+
+func (r renderer) text(n *Node) string {
+    text := n.Text
+    var href string
+    var code, strong, em, strike bool
+    for _, mark := range n.Marks {
+        switch mark.Type {
+        case MarkLink:
+            href = attrString(mark.Attrs, "href")
+        case MarkCode:
+            code = true
+        case MarkStrong:
+            strong = true
+        case MarkEm:
+            em = true
+        case MarkStrike:
+            strike = true
+        }
+    }
+
+    if code {
+        text = "`" + text + "`"
+    }
+    if r.style == terminalStyle {
+        if href != "" && href != n.Text {
+            text += " (" + href + ")"
+        }
+        return text
+    }
+
+    if strike {
+        text = "~~" + text + "~~"
+    }
+    if em {
+        text = "_" + text + "_"
+    }
+    if strong {
+        text = "**" + text + "**"
+    }
+    if href != "" {
+        text = "[" + text + "](" + href + ")"
+    }
+    return text
+}
+
+// plainText concatenates the text of a node and its descendants.
+func plainText(n *Node) string {
+    var b strings.Builder
+    b.WriteString(n.Text)
+    for _, child := range n.Content {
+        if child.Type == TypeHardBreak {
+            b.WriteString("\n")
+        }
+        b.WriteString(plainText(child))
+    }
+    return b.String()
+}
+
+// indent prefixes lines, using blankPrefix for empty lines so that no
+// trailing whitespace is left.
+func indent(lines []string, prefix, blankPrefix string) []string {
+    indented := make([]string, len(lines))
+    for i, line := range lines {
+        if line == "" {
+            indented[i] = blankPrefix
+        } else {
+            indented[i] = prefix + line
+        }
+    }
+    return indented
+}
+
+func panelLabel(panelType string) string {
+    if panelType == "" {
+        return "Info"
+    }
+    return strings.ToUpper(panelType[:1]) + panelType[1:]
+}
+
+// formatTimestamp formats the milliseconds since the epoch of a date node.
+func formatTimestamp(timestamp string) string {
+    ms, err := strconv.ParseInt(timestamp, 10, 64)
+    if err != nil {
+        return timestamp
+    }
+    return time.Unix(ms/1000, 0).UTC().Format("2006-01-02")
+}
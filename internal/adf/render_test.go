@@ -0,0 +1,63 @@
+package adf
+
+import (
+    "encoding/json"
+    "flag"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestRenderGolden renders each testdata/*.json document and compares it
+// with the .txt (Terminal) and .md (Markdown) files next to it. Run with
+// -update to rewrite them after an intended change.
+func TestRenderGolden(t *testing.T) {
+    inputs, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(inputs) == 0 {
+        t.Fatal("no documents in testdata")
+    }
+    renderers := []struct {
+        ext    string
+        render func(*Node) string
+    }{
+        {".txt", Terminal},
+        {".md", Markdown},
+    }
+
+    for _, input := range inputs {
+        data, err := os.ReadFile(input)
+        if err != nil {
+            t.Fatal(err)
+        }
+        var doc Node
+        if err := json.Unmarshal(data, &doc); err != nil {
+            t.Fatalf("%s: %v", input, err)
+        }
+
+        for _, r := range renderers {
+            golden := strings.TrimSuffix(input, ".json") + r.ext
+            t.Run(filepath.Base(golden), func(t *testing.T) {
+                got := r.render(&doc) + "\n"
+                if *update {
+                    if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+                        t.Fatal(err)
+                    }
+                    return
+                }
+                want, err := os.ReadFile(golden)
+                if err != nil {
+                    t.Fatalf("%v (run go test -update to create it)", err)
+                }
+                if got != string(want) {
+                    t.Errorf("%s differs\n--- got\n%s--- want\n%s", golden, got, want)
+                }
+            })
+        }
+    }
+}
@@ -0,0 +1,262 @@
+package changelog
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "regexp"
+    "sort"
+    "strings"
+
+    "jira-tools/internal/git"
+)
+
+// Changelog groupings.
+const (
+    GroupByType      = "type"
+    GroupByIssueType = "issuetype"
+    GroupByEpic      = "epic"
+)
+
+// Unreleased is the version name of changes that have not been released.
+const Unreleased = "Unreleased"
+
+// OtherType is the type of commits without a conventional type.
+const OtherType = "other"
+
+// typeTitles names the sections of the conventional types, in the order
+// they are listed.
+var typeTitles = []struct{ Type, Title string }{
+    {"feat", "Features"},
+    {"fix", "Bug Fixes"},
+    {"perf", "Performance"},
+    {"refactor", "Refactoring"},
+    {"revert", "Reverts"},
+    {"docs", "Documentation"},
+    {"style", "Style"},
+    {"test", "Tests"},
+    {"build", "Build"},
+    {"ci", "Continuous Integration"},
+    {"chore", "Chores"},
+    {OtherType, "Other Changes"},
+}
+
+// Entry is a change: one commit, or every commit of the same type that
+// references the same issue.
+type Entry struct {
+    Type     string   `json:"type"`
+    Breaking bool     `json:"breaking,omitempty"`
+    IssueKey string   `json:"issue_key,omitempty"`
+    // Title is the Jira summary when known, the commit description otherwise.
+    Title     string   `json:"title"`
+    Commits   []string `json:"commits"`
+    URL       string   `json:"url,omitempty"`
+    IssueType string   `json:"issue_type,omitempty"`
+    EpicKey   string   `json:"epic_key,omitempty"`
+    Epic      string   `json:"epic,omitempty"`
+}
+
+// Group is a titled section of entries.
+type Group struct {
+    Title   string  `json:"title"`
+    Entries []Entry `json:"entries"`
+}
+
+// Changelog is the set of changes of one version.
+type Changelog struct {
+    Version string  `json:"version"`
+    Date    string  `json:"date,omitempty"`
+    From    string  `json:"from,omitempty"`
+    To      string  `json:"to"`
+    Groups  []Group `json:"groups"`
+}
+
+// FromCommits turns commits into entries. Merge, revert and fixup commits
+// created by git are skipped; the issue key is taken from the conventional
+// scope when it is one, from anywhere in the message otherwise.
+func FromCommits(commits []git.Commit, keyPattern *regexp.Regexp) []Entry {
+    var entries []Entry
+    index := make(map[string]int)
+    for _, commit := range commits {
+        if git.IsGeneratedCommitMessage(commit.Subject) {
+            continue
+        }
+
+        entry := Entry{Type: OtherType, Title: commit.Subject}
+        if parsed, ok := git.ParseConventionalSubject(commit.Subject); ok {
+            entry.Type = parsed.Type
+            entry.Breaking = parsed.Breaking
+            entry.Title = parsed.Description
+            entry.IssueKey = keyPattern.FindString(parsed.Scope)
+        }
+        if entry.IssueKey == "" {
+            entry.IssueKey = keyPattern.FindString(commit.Message())
+        }
+        if strings.Contains(commit.Body, "BREAKING CHANGE") {
+            entry.Breaking = true
+        }
+
+        // Several commits for the same issue make a single entry
+        if entry.IssueKey != "" {
+            id := entry.Type + " " + entry.IssueKey
+            if i, ok := index[id]; ok {
+                entries[i].Commits = append(entries[i].Commits, commit.Hash)
+                entries[i].Breaking = entries[i].Breaking || entry.Breaking
+                continue
+            }
+            index[id] = len(entries)
+        }
+        entry.Commits = []string{commit.Hash}
+        entries = append(entries, entry)
+    }
+    return entries
+}
+
+// GroupEntries sorts entries into sections by conventional type, Jira issue
+// type or epic.
+func GroupEntries(entries []Entry, by string) ([]Group, error) {
+    var keyOf func(Entry) string
+    var order []string
+    titles := make(map[string]string)
+
+    switch by {
+    case GroupByType, "":
+        keyOf = func(e Entry) string { return e.Type }
+        for _, t := range typeTitles {
+            order = append(order, t.Type)
+            titles[t.Type] = t.Title
+        }
+    case GroupByIssueType:
+        keyOf = func(e Entry) string { return e.IssueType }
+        titles[""] = "Other Changes"
+    case GroupByEpic:
+        keyOf = func(e Entry) string { return e.EpicKey }
+        titles[""] = "No Epic"
+    default:
+        return nil, fmt.Errorf("invalid grouping %q, expected %s, %s or %s", by, GroupByType, GroupByIssueType, GroupByEpic)
+    }
+
+    grouped := make(map[string][]Entry)
+    var extra []string
+    for _, entry := range entries {
+        key := keyOf(entry)
+        if _, ok := grouped[key]; !ok && !containsString(order, key) {
+            extra = append(extra, key)
+        }
+        grouped[key] = append(grouped[key], entry)
+        if by == GroupByEpic && key != "" && entry.Epic != "" {
+            titles[key] = fmt.Sprintf("%s (%s)", entry.Epic, key)
+        }
+    }
+
+    // Known types first, then the rest alphabetically with the catch-all last
+    sort.Slice(extra, func(i, j int) bool {
+        if extra[i] == "" || extra[j] == "" {
+            return extra[j] == ""
+        }
+        return extra[i] < extra[j]
+    })
+    order = append(order, extra...)
+
+    var groups []Group
+    for _, key := range order {
+        if len(grouped[key]) == 0 {
+            continue
+        }
+        title := titles[key]
+        if title == "" {
+            title = key
+        }
+        groups = append(groups, Group{Title: title, Entries: grouped[key]})
+    }
+    return groups, nil
+}
+
+func containsString(values []string, value string) bool {
+    for _, v := range values {
+        if v == value {
+            return true
+        }
+    }
+    return false
+}
+
+// heading returns "[1.2.0] - 2024-05-02" or "[Unreleased]".
+func (c *Changelog) heading() string {
+    if c.Date == "" || c.Version == Unreleased {
+        return "[" + c.Version + "]"
+    }
+    return fmt.Sprintf("[%s] - %s", c.Version, c.Date)
+}
+
+// markdownLine renders an entry as a Markdown list item.
+func markdownLine(entry Entry) string {
+    var b strings.Builder
+    b.WriteString("- ")
+    if entry.Breaking {
+        b.WriteString("**BREAKING** ")
+    }
+    b.WriteString(entry.Title)
+    switch {
+    case entry.IssueKey != "" && entry.URL != "":
+        fmt.Fprintf(&b, " ([%s](%s))", entry.IssueKey, entry.URL)
+    case entry.IssueKey != "":
+        fmt.Fprintf(&b, " (%s)", entry.IssueKey)
+    case len(entry.Commits) > 0:
+        fmt.Fprintf(&b, " (%s)", shortHash(entry.Commits[0]))
+    }
+    return b.String()
+}
+
+// WriteMarkdown writes the changelog as a Markdown section.
+func (c *Changelog) WriteMarkdown(w io.Writer) error {
+    fmt.Fprintf(w, "## %s\n", c.heading())
+    for _, group := range c.Groups {
+        fmt.Fprintf(w, "\n### %s\n\n", group.Title)
+        for _, entry := range group.Entries {
+            fmt.Fprintln(w, markdownLine(entry))
+        }
+    }
+    return nil
+}
+
+// WriteText writes the changelog as plain text.
+func (c *Changelog) WriteText(w io.Writer) error {
+    title := c.Version
+    if c.Date != "" && c.Version != Unreleased {
+        title += " (" + c.Date + ")"
+    }
+    fmt.Fprintln(w, title)
+    for _, group := range c.Groups {
+        fmt.Fprintf(w, "\n%s\n", group.Title)
+        for _, entry := range group.Entries {
+            ref := entry.IssueKey
+            if ref == "" && len(entry.Commits) > 0 {
+                ref = shortHash(entry.Commits[0])
+            }
+            title := entry.Title
+            if entry.Breaking {
+                title = "BREAKING: " + title
+            }
+            fmt.Fprintf(w, "  %-10s %s\n", ref, title)
+            if entry.URL != "" {
+                fmt.Fprintf(w, "  %-10s %s\n", "", entry.URL)
+            }
+        }
+    }
+    return nil
+}
+
+// WriteJSON writes the changelog as indented JSON.
+func (c *Changelog) WriteJSON(w io.Writer) error {
+    encoder := json.NewEncoder(w)
+    encoder.SetIndent("", "  ")
+    return encoder.Encode(c)
+}
+
+func shortHash(hash string) string {
+    if len(hash) > 7 {
+        return hash[:7]
+    }
+    return hash
+}
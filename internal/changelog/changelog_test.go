@@ -0,0 +1,142 @@
+package changelog
+
+import (
+    "regexp"
+    "strings"
+    "testing"
+
+    "jira-tools/internal/git"
+)
+
+var testKeyPattern = regexp.MustCompile(git.DefaultIssueKeyPattern)
+
+func TestFromCommits(t *testing.T) {
+    commits := []git.Commit{
+        {Hash: "aaaaaaa1", Subject: "feat(PROJ-1): add login"},
+        {Hash: "bbbbbbb2", Subject: "fix: handle empty password", Body: "Refs PROJ-2"},
+        {Hash: "ccccccc3", Subject: "feat(PROJ-1): remember the user"},
+        {Hash: "ddddddd4", Subject: "fix(PROJ-1): typo on the login page"},
+        {Hash: "eeeeeee5", Subject: "feat(api)!: drop v1 endpoints"},
+        {Hash: "fffffff6", Subject: "refactor: split the client", Body: "BREAKING CHANGE: NewClient takes options"},
+        {Hash: "ggggggg7", Subject: "Update README"},
+        {Hash: "hhhhhhh8", Subject: "Merge branch 'feature/PROJ-3' into develop"},
+        {Hash: "iiiiiii9", Subject: "fixup! feat(PROJ-1): add login"},
+    }
+    want := []Entry{
+        {Type: "feat", IssueKey: "PROJ-1", Title: "add login", Commits: []string{"aaaaaaa1", "ccccccc3"}},
+        {Type: "fix", IssueKey: "PROJ-2", Title: "handle empty password", Commits: []string{"bbbbbbb2"}},
+        {Type: "fix", IssueKey: "PROJ-1", Title: "typo on the login page", Commits: []string{"ddddddd4"}},
+        {Type: "feat", Breaking: true, Title: "drop v1 endpoints", Commits: []string{"eeeeeee5"}},
+        {Type: "refactor", Breaking: true, Title: "split the client", Commits: []string{"fffffff6"}},
+        {Type: OtherType, Title: "Update README", Commits: []string{"ggggggg7"}},
+    }
+
+    got := FromCommits(commits, testKeyPattern)
+    if len(got) != len(want) {
+        t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+    }
+    for i := range want {
+        if !equalEntries(got[i], want[i]) {
+            t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+        }
+    }
+}
+
+func TestGroupEntries(t *testing.T) {
+    entries := []Entry{
+        {Type: "fix", Title: "a", IssueType: "Bug", EpicKey: "PROJ-10", Epic: "Login"},
+        {Type: "chore", Title: "b", IssueType: "Task"},
+        {Type: "feat", Title: "c", IssueType: "Story", EpicKey: "PROJ-10", Epic: "Login"},
+        {Type: "wip", Title: "d", EpicKey: "PROJ-20"},
+        {Type: OtherType, Title: "e", IssueType: "Bug", EpicKey: "PROJ-5", Epic: "Billing"},
+    }
+    tests := []struct {
+        by   string
+        want []string
+    }{
+        {by: GroupByType, want: []string{"Features: c", "Bug Fixes: a", "Chores: b", "Other Changes: e", "wip: d"}},
+        {by: "", want: []string{"Features: c", "Bug Fixes: a", "Chores: b", "Other Changes: e", "wip: d"}},
+        {by: GroupByIssueType, want: []string{"Bug: a e", "Story: c", "Task: b", "Other Changes: d"}},
+        {by: GroupByEpic, want: []string{"Login (PROJ-10): a c", "PROJ-20: d", "Billing (PROJ-5): e", "No Epic: b"}},
+    }
+    for _, tt := range tests {
+        groups, err := GroupEntries(entries, tt.by)
+        if err != nil {
+            t.Fatalf("GroupEntries(%q): %v", tt.by, err)
+        }
+        var got []string
+        for _, group := range groups {
+            var titles []string
+            for _, entry := range group.Entries {
+                titles = append(titles, entry.Title)
+            }
+            got = append(got, group.Title+": "+strings.Join(titles, " "))
+        }
+        if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+            t.Errorf("GroupEntries(%q) =\n%s\nwant\n%s", tt.by, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+        }
+    }
+
+    if _, err := GroupEntries(entries, "author"); err == nil {
+        t.Error("GroupEntries with an unknown grouping succeeded")
+    }
+}
+
+func TestKeepAChangelogGroups(t *testing.T) {
+    entries := []Entry{
+        {Type: "fix", Title: "a"},
+        {Type: "chore", Title: "b"},
+        {Type: "feat", Title: "c"},
+        {Type: "perf", Title: "d"},
+        {Type: "refactor", Title: "e"},
+    }
+    var got []string
+    for _, group := range KeepAChangelogGroups(entries) {
+        for _, entry := range group.Entries {
+            got = append(got, group.Title+" "+entry.Title)
+        }
+    }
+    want := []string{"Added c", "Changed d", "Changed e", "Fixed a"}
+    if strings.Join(got, ", ") != strings.Join(want, ", ") {
+        t.Errorf("groups = %v, want %v", got, want)
+    }
+}
+
+func TestWriteMarkdown(t *testing.T) {
+    c := &Changelog{
+        Version: "1.2.0",
+        Date:    "2024-05-02",
+        Groups: []Group{
+            {Title: "Added", Entries: []Entry{
+                {Title: "add login", IssueKey: "PROJ-1", URL: "https://acme.atlassian.net/browse/PROJ-1"},
+                {Title: "drop v1", Breaking: true, Commits: []string{"eeeeeee5ffff"}},
+            }},
+            {Title: "Fixed", Entries: []Entry{{Title: "typo", IssueKey: "PROJ-2"}}},
+        },
+    }
+    var b strings.Builder
+    if err := c.WriteMarkdown(&b); err != nil {
+        t.Fatal(err)
+    }
+    want := `## [1.2.0] - 2024-05-02
+
+### Added
+
+- add login ([PROJ-1](https://acme.atlassian.net/browse/PROJ-1))
+- **BREAKING** drop v1 (eeeeeee)
+
+### Fixed
+
+- typo (PROJ-2)
+`
+    if b.String() != want {
+        t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+    }
+}
+
+func equalEntries(a, b Entry) bool {
+    if a.Type != b.Type || a.Breaking != b.Breaking || a.IssueKey != b.IssueKey || a.Title != b.Title {
+        return false
+    }
+    return strings.Join(a.Commits, " ") == strings.Join(b.Commits, " ")
+}
@@ -0,0 +1,200 @@
+package changelog
+
+import (
+    "fmt"
+    "os"
+    "regexp"
+    "strings"
+)
+
+// keepAChangelogSections maps conventional types to the sections of the
+// Keep a Changelog format. Other types, such as chores, are left out.
+var keepAChangelogSections = []struct {
+    Title string
+    Types []string
+}{
+    {"Added", []string{"feat"}},
+    {"Changed", []string{"perf", "refactor"}},
+    {"Deprecated", []string{"deprecate"}},
+    {"Removed", []string{"remove"}},
+    {"Fixed", []string{"fix"}},
+    {"Security", []string{"security"}},
+}
+
+const keepAChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// versionHeading matches "## [1.2.0] - 2024-05-02" and "## [Unreleased]".
+var versionHeading = regexp.MustCompile(`^## \[([^\]]+)\]`)
+
+// linkDefinition matches the compare links at the end of the file.
+var linkDefinition = regexp.MustCompile(`^\[[^\]]+\]: `)
+
+// KeepAChangelogGroups sorts entries into Keep a Changelog sections.
+func KeepAChangelogGroups(entries []Entry) []Group {
+    var groups []Group
+    for _, section := range keepAChangelogSections {
+        group := Group{Title: section.Title}
+        for _, entry := range entries {
+            if containsString(section.Types, entry.Type) {
+                group.Entries = append(group.Entries, entry)
+            }
+        }
+        if len(group.Entries) > 0 {
+            groups = append(groups, group)
+        }
+    }
+    return groups
+}
+
+// UpdateFile writes the section of version into a Keep a Changelog file,
+// creating the file if needed. An existing section of the same version is
+// replaced. A released version goes right below [Unreleased], whose
+// entries are merged into it since they are the changes being released.
+func UpdateFile(path, version, section string) error {
+    content, err := os.ReadFile(path)
+    if err != nil && !os.IsNotExist(err) {
+        return err
+    }
+    if len(content) == 0 {
+        content = []byte(keepAChangelogHeader)
+    }
+
+    // Split the file into the preamble and one block per version
+    var preamble []string
+    var blocks []versionBlock
+    for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+        if match := versionHeading.FindStringSubmatch(line); match != nil {
+            blocks = append(blocks, versionBlock{version: match[1]})
+        } else if len(blocks) == 0 {
+            preamble = append(preamble, line)
+            continue
+        }
+        last := &blocks[len(blocks)-1]
+        last.lines = append(last.lines, line)
+    }
+
+    // The link definitions at the end of the file are its footer. Link
+    // definitions within a section stay in it.
+    var links []string
+    if len(blocks) > 0 {
+        last := &blocks[len(blocks)-1]
+        end := len(last.lines)
+        for end > 1 && (strings.TrimSpace(last.lines[end-1]) == "" || linkDefinition.MatchString(last.lines[end-1])) {
+            end--
+        }
+        for _, line := range last.lines[end:] {
+            if line != "" {
+                links = append(links, line)
+            }
+        }
+        last.lines = last.lines[:end]
+    }
+
+    updated := versionBlock{version: version, lines: strings.Split(strings.TrimRight(section, "\n"), "\n")}
+    replaced := false
+    for i := range blocks {
+        if blocks[i].version == version {
+            blocks[i] = updated
+            replaced = true
+        }
+    }
+    if !replaced {
+        // A new release takes over whatever was unreleased, including the
+        // entries written by hand
+        if len(blocks) > 0 && blocks[0].version == Unreleased {
+            if version != Unreleased {
+                updated.lines = mergeSections(updated.lines, blocks[0].lines)
+            }
+            blocks = blocks[1:]
+        }
+        head := []versionBlock{updated}
+        if version != Unreleased {
+            unreleased := versionBlock{version: Unreleased, lines: []string{"## [" + Unreleased + "]"}}
+            head = []versionBlock{unreleased, updated}
+        }
+        blocks = append(head, blocks...)
+    }
+
+    var out []string
+    out = append(out, strings.TrimRight(strings.Join(preamble, "\n"), "\n"), "")
+    for _, block := range blocks {
+        out = append(out, strings.TrimRight(strings.Join(block.lines, "\n"), "\n"), "")
+    }
+    out = append(out, links...)
+
+    data := strings.TrimRight(strings.Join(out, "\n"), "\n") + "\n"
+    if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+        return fmt.Errorf("failed to write %s: %v", path, err)
+    }
+    return nil
+}
+
+// versionBlock is the section of one version, heading included.
+type versionBlock struct {
+    version string
+    lines   []string
+}
+
+// subsection is a "### Added" part of a version section. The lines above
+// the first subsection have no title.
+type subsection struct {
+    title string
+    lines []string
+}
+
+// splitSubsections splits the lines of a version section, without its
+// heading, by subsection. Blank lines are dropped.
+func splitSubsections(lines []string) []subsection {
+    parts := []subsection{{}}
+    for _, line := range lines {
+        if strings.HasPrefix(line, "### ") {
+            parts = append(parts, subsection{title: strings.TrimSpace(line[4:])})
+            continue
+        }
+        if strings.TrimSpace(line) != "" {
+            last := &parts[len(parts)-1]
+            last.lines = append(last.lines, line)
+        }
+    }
+    return parts
+}
+
+// mergeSections adds the lines of the unreleased section to the generated
+// section of a release, under the subsection of the same title. Lines the
+// release already has are not repeated.
+func mergeSections(release, unreleased []string) []string {
+    parts := splitSubsections(release[1:])
+    for _, extra := range splitSubsections(unreleased[1:]) {
+        i := 0
+        for i < len(parts) && parts[i].title != extra.title {
+            i++
+        }
+        if i == len(parts) {
+            parts = append(parts, subsection{title: extra.title})
+        }
+        for _, line := range extra.lines {
+            if !containsString(parts[i].lines, line) {
+                parts[i].lines = append(parts[i].lines, line)
+            }
+        }
+    }
+
+    merged := []string{release[0]}
+    for _, part := range parts {
+        if len(part.lines) == 0 {
+            continue
+        }
+        merged = append(merged, "")
+        if part.title != "" {
+            merged = append(merged, "### "+part.title, "")
+        }
+        merged = append(merged, part.lines...)
+    }
+    return merged
+}
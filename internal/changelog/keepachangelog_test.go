@@ -0,0 +1,245 @@
+package changelog
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+const testHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+`
+
+func TestUpdateFile(t *testing.T) {
+    release := "## [1.2.0] - 2024-05-02\n\n### Added\n\n- add login (PROJ-1)\n"
+    tests := []struct {
+        name    string
+        initial string
+        version string
+        section string
+        want    string
+        // rerun is set when writing the version again replaces the
+        // entries merged from Unreleased
+        rerun bool
+    }{
+        {
+            name:    "missing file",
+            initial: "-",
+            version: "1.2.0",
+            section: release,
+            want: keepAChangelogHeader + `
+## [Unreleased]
+
+## [1.2.0] - 2024-05-02
+
+### Added
+
+- add login (PROJ-1)
+`,
+        },
+        {
+            name: "new release",
+            initial: testHeader + `
+## [Unreleased]
+
+## [1.1.0] - 2024-04-01
+
+### Fixed
+
+- typo (PROJ-2)
+`,
+            version: "1.2.0",
+            section: release,
+            want: testHeader + `
+## [Unreleased]
+
+## [1.2.0] - 2024-05-02
+
+### Added
+
+- add login (PROJ-1)
+
+## [1.1.0] - 2024-04-01
+
+### Fixed
+
+- typo (PROJ-2)
+`,
+        },
+        {
+            name: "existing version replaced",
+            initial: testHeader + `
+## [Unreleased]
+
+## [1.2.0] - 2024-05-01
+
+### Added
+
+- outdated entry
+
+## [1.1.0] - 2024-04-01
+
+- first release
+`,
+            version: "1.2.0",
+            section: release,
+            want: testHeader + `
+## [Unreleased]
+
+## [1.2.0] - 2024-05-02
+
+### Added
+
+- add login (PROJ-1)
+
+## [1.1.0] - 2024-04-01
+
+- first release
+`,
+        },
+        {
+            name: "hand-written unreleased entries kept",
+            initial: testHeader + `
+## [Unreleased]
+
+Upgrade notes are in UPGRADING.md.
+
+### Added
+
+- add login (PROJ-1)
+- dark mode, written by hand
+
+### Security
+
+- rotate the signing keys
+
+## [1.1.0] - 2024-04-01
+
+- first release
+`,
+            version: "1.2.0",
+            section: release,
+            rerun:   true,
+            want: testHeader + `
+## [Unreleased]
+
+## [1.2.0] - 2024-05-02
+
+Upgrade notes are in UPGRADING.md.
+
+### Added
+
+- add login (PROJ-1)
+- dark mode, written by hand
+
+### Security
+
+- rotate the signing keys
+
+## [1.1.0] - 2024-04-01
+
+- first release
+`,
+        },
+        {
+            name: "link definitions footer",
+            initial: testHeader + `
+## [Unreleased]
+
+## [1.1.0] - 2024-04-01
+
+- fix the crash reported in [#12]
+
+[#12]: https://github.com/acme/app/issues/12
+
+## [1.0.0] - 2024-03-01
+
+- first release
+
+[Unreleased]: https://github.com/acme/app/compare/v1.1.0...HEAD
+[1.1.0]: https://github.com/acme/app/compare/v1.0.0...v1.1.0
+`,
+            version: "1.0.0",
+            section: "## [1.0.0] - 2024-03-01\n\n- first public release\n",
+            want: testHeader + `
+## [Unreleased]
+
+## [1.1.0] - 2024-04-01
+
+- fix the crash reported in [#12]
+
+[#12]: https://github.com/acme/app/issues/12
+
+## [1.0.0] - 2024-03-01
+
+- first public release
+
+[Unreleased]: https://github.com/acme/app/compare/v1.1.0...HEAD
+[1.1.0]: https://github.com/acme/app/compare/v1.0.0...v1.1.0
+`,
+        },
+        {
+            name: "unreleased replaced",
+            initial: testHeader + `
+## [Unreleased]
+
+### Added
+
+- old preview
+
+## [1.1.0] - 2024-04-01
+
+- first release
+`,
+            version: Unreleased,
+            section: "## [Unreleased]\n\n### Added\n\n- add login (PROJ-1)\n",
+            want: testHeader + `
+## [Unreleased]
+
+### Added
+
+- add login (PROJ-1)
+
+## [1.1.0] - 2024-04-01
+
+- first release
+`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+            if tt.initial != "-" {
+                if err := os.WriteFile(path, []byte(tt.initial), 0644); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            if err := UpdateFile(path, tt.version, tt.section); err != nil {
+                t.Fatalf("UpdateFile: %v", err)
+            }
+            data, err := os.ReadFile(path)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if string(data) != tt.want {
+                t.Errorf("got:\n%s\nwant:\n%s", data, tt.want)
+            }
+
+            // Writing the same version again changes nothing
+            if tt.rerun {
+                return
+            }
+            if err := UpdateFile(path, tt.version, tt.section); err != nil {
+                t.Fatalf("UpdateFile again: %v", err)
+            }
+            again, err := os.ReadFile(path)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if string(again) != string(data) {
+                t.Errorf("second update changed the file:\n%s", again)
+            }
+        })
+    }
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+    "fmt"
+    "strings"
+)
+
+// FallbackCommitType is used when neither the issue type nor the branch
+// type maps to a commit type.
+const FallbackCommitType = "chore"
+
+// DefaultAllowedCommitTypes are the conventional commit types jt accepts
+// unless a project configures its own list.
+var DefaultAllowedCommitTypes = []string{"feat", "fix", "chore", "docs", "style", "refactor", "test"}
+
+// DefaultIssueTypeCommitTypes maps Jira issue types to commit types.
+var DefaultIssueTypeCommitTypes = map[string]string{
+    "bug":         "fix",
+    "story":       "feat",
+    "new feature": "feat",
+    "improvement": "feat",
+    "epic":        "feat",
+    "task":        "chore",
+    "sub-task":    "chore",
+    "subtask":     "chore",
+}
+
+// DefaultBranchTypeCommitTypes maps branch type prefixes to commit types.
+var DefaultBranchTypeCommitTypes = map[string]string{
+    "feature": "feat",
+    "bugfix":  "fix",
+    "hotfix":  "fix",
+    "release": "chore",
+}
+
+// CommitTypesConfig controls which commit types are valid and how jt picks
+// one when none is given. Entries are merged over the defaults.
+type CommitTypesConfig struct {
+    Allowed     []string          `json:"allowed,omitempty"`
+    IssueTypes  map[string]string `json:"issue_types,omitempty"`
+    BranchTypes map[string]string `json:"branch_types,omitempty"`
+    Default     string            `json:"default,omitempty"`
+}
+
+// AllowedTypes returns the configured commit types, or the defaults.
+func (c CommitTypesConfig) AllowedTypes() []string {
+    if len(c.Allowed) > 0 {
+        return c.Allowed
+    }
+    return DefaultAllowedCommitTypes
+}
+
+// Validate returns an error when commitType is not an allowed type.
+func (c CommitTypesConfig) Validate(commitType string) error {
+    for _, allowed := range c.AllowedTypes() {
+        if commitType == allowed {
+            return nil
+        }
+    }
+    return fmt.Errorf("invalid commit type %q (allowed: %s)", commitType, strings.Join(c.AllowedTypes(), ", "))
+}
+
+// fixBranchTypes are the branch types that always carry fixes, whatever
+// the issue type.
+var fixBranchTypes = map[string]bool{"bugfix": true, "hotfix": true}
+
+// Infer picks the commit type for an issue type and branch type. Bugfix and
+// hotfix branches decide first, so a Task fixed on hotfix/ is a fix; on other
+// branches the issue type wins over the branch type. Issue types match
+// case-insensitively.
+func (c CommitTypesConfig) Infer(issueType, branchType string) string {
+    branchCommitType := lookupType(c.BranchTypes, DefaultBranchTypeCommitTypes, branchType)
+    if fixBranchTypes[branchType] && branchCommitType != "" {
+        return branchCommitType
+    }
+    if commitType := lookupType(c.IssueTypes, DefaultIssueTypeCommitTypes, strings.ToLower(issueType)); commitType != "" {
+        return commitType
+    }
+    if branchCommitType != "" {
+        return branchCommitType
+    }
+    if c.Default != "" {
+        return c.Default
+    }
+    return FallbackCommitType
+}
+
+func lookupType(configured, defaults map[string]string, key string) string {
+    if key == "" {
+        return ""
+    }
+    for name, commitType := range configured {
+        if strings.EqualFold(name, key) {
+            return commitType
+        }
+    }
+    return defaults[key]
+}
@@ -0,0 +1,48 @@
+package config
+
+import "testing"
+
+func TestCommitTypesInfer(t *testing.T) {
+    tests := []struct {
+        name       string
+        config     CommitTypesConfig
+        issueType  string
+        branchType string
+        want       string
+    }{
+        {name: "issue type", issueType: "Story", branchType: "feature", want: "feat"},
+        {name: "issue type case-insensitive", issueType: "BUG", want: "fix"},
+        {name: "issue type over feature branch", issueType: "Task", branchType: "feature", want: "chore"},
+        {name: "task on hotfix branch", issueType: "Task", branchType: "hotfix", want: "fix"},
+        {name: "story on bugfix branch", issueType: "Story", branchType: "bugfix", want: "fix"},
+        {name: "branch type without issue type", branchType: "release", want: "chore"},
+        {name: "unknown issue and branch type", issueType: "Spike", branchType: "experiment", want: FallbackCommitType},
+        {
+            name:       "configured issue type",
+            config:     CommitTypesConfig{IssueTypes: map[string]string{"Improvement": "perf"}},
+            issueType:  "improvement",
+            branchType: "feature",
+            want:       "perf",
+        },
+        {
+            name:       "configured hotfix branch type",
+            config:     CommitTypesConfig{BranchTypes: map[string]string{"hotfix": "perf"}},
+            issueType:  "Bug",
+            branchType: "hotfix",
+            want:       "perf",
+        },
+        {
+            name:      "configured default",
+            config:    CommitTypesConfig{Default: "docs"},
+            issueType: "Spike",
+            want:      "docs",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := tt.config.Infer(tt.issueType, tt.branchType); got != tt.want {
+                t.Errorf("Infer(%q, %q) = %q, want %q", tt.issueType, tt.branchType, got, tt.want)
+            }
+        })
+    }
+}
@@ -7,11 +7,162 @@ import (
     "path/filepath"
 )
 
+// Staging modes for `jt commit`.
+const (
+    CommitModeStaged = "staged"
+    CommitModeAll    = "all"
+)
+
+// Lifecycle events that transition rules can be attached to.
+const (
+    EventBranch = "branch"
+    EventPush   = "push"
+    EventFinish = "finish"
+    EventPR     = "pr"
+)
+
+// Merge strategies for `jt finish`.
+const (
+    MergeNoFF   = "no-ff"
+    MergeFF     = "ff"
+    MergeSquash = "squash"
+)
+
 type BranchConfig struct {
-    ProjectPath        string `json:"project_path"`
-    ProductionBranch   string `json:"production_branch,omitempty"`
-    DevelopmentBranch  string `json:"development_branch"`
-    IsMonorepo         bool   `json:"is_monorepo"`
+    ProjectPath       string           `json:"project_path"`
+    ProductionBranch  string           `json:"production_branch,omitempty"`
+    DevelopmentBranch string           `json:"development_branch"`
+    IsMonorepo        bool             `json:"is_monorepo"`
+    Transitions       []TransitionRule `json:"transitions,omitempty"`
+    // IssueKeyPattern is a regular expression matching issue keys in branch
+    // names. Empty means the standard PROJ-123 form.
+    IssueKeyPattern string `json:"issue_key_pattern,omitempty"`
+    // BranchTemplate is a text/template for branch names, e.g.
+    // "{{.Type}}/{{.Key}}-{{.Slug}}". BranchTemplates overrides it per
+    // branch type.
+    BranchTemplate  string            `json:"branch_template,omitempty"`
+    BranchTemplates map[string]string `json:"branch_templates,omitempty"`
+    // BranchMaxLength truncates the slug of longer branch names at a word
+    // boundary. Zero means no limit.
+    BranchMaxLength int        `json:"branch_max_length,omitempty"`
+    Slug            SlugConfig `json:"slug"`
+    // CommitTemplate is a text/template for commit messages. Empty means
+    // "type(KEY): summary".
+    CommitTemplate string `json:"commit_template,omitempty"`
+    // CommitMode is what `jt commit` stages when no staging flag is given:
+    // CommitModeStaged (the default) or CommitModeAll.
+    CommitMode  string            `json:"commit_mode,omitempty"`
+    CommitTypes CommitTypesConfig `json:"commit_types"`
+    Hooks       HooksConfig       `json:"hooks"`
+    Finish      FinishConfig      `json:"finish"`
+    Release     ReleaseConfig     `json:"release"`
+    PullRequest PullRequestConfig `json:"pull_request"`
+    // Profile is the Jira profile of the project. Empty means
+    // DefaultProfile.
+    Profile string `json:"profile,omitempty"`
+}
+
+// PullRequestConfig controls `jt pr`.
+type PullRequestConfig struct {
+    // Provider is github, gitlab or bitbucket. Empty means detected from
+    // the host of origin.
+    Provider string `json:"provider,omitempty"`
+    // APIURL overrides the REST API root, e.g. for GitHub Enterprise or a
+    // self-hosted GitLab.
+    APIURL string `json:"api_url,omitempty"`
+    // Repository is the owner/name path. Empty means taken from origin.
+    Repository string `json:"repository,omitempty"`
+    // TokenEnv names the environment variable holding the API token. Empty
+    // means GITHUB_TOKEN, GITLAB_TOKEN or BITBUCKET_TOKEN.
+    TokenEnv string `json:"token_env,omitempty"`
+    // TitleTemplate and BodyTemplate are text/templates for the pull
+    // request. Empty means "KEY: summary" and the issue link and description.
+    TitleTemplate string `json:"title_template,omitempty"`
+    BodyTemplate  string `json:"body_template,omitempty"`
+    Draft         bool   `json:"draft,omitempty"`
+}
+
+// ReleaseConfig controls the Jira side of `jt release`.
+type ReleaseConfig struct {
+    // Project is a Jira project that always gets the release version, in
+    // addition to the projects of the issues in the release.
+    Project string `json:"project,omitempty"`
+}
+
+// FinishConfig controls how `jt finish` merges Git Flow branches.
+type FinishConfig struct {
+    // Merge is MergeNoFF (default), MergeFF or MergeSquash.
+    Merge string `json:"merge,omitempty"`
+    // TagPrefix is put in front of release and hotfix version tags. Unset
+    // means "v"; set it to "" for bare version tags.
+    TagPrefix *string `json:"tag_prefix,omitempty"`
+    // KeepRemote keeps the remote branch after finishing.
+    KeepRemote bool `json:"keep_remote,omitempty"`
+}
+
+// GetTagPrefix returns the prefix of version tags.
+func (c FinishConfig) GetTagPrefix() string {
+    if c.TagPrefix == nil {
+        return "v"
+    }
+    return *c.TagPrefix
+}
+
+// Commit message requirements enforced by the commit-msg hook.
+const (
+    RequireKey          = "key"
+    RequireConventional = "conventional"
+    RequireBoth         = "both"
+)
+
+// HooksConfig controls the git hooks installed by `jt hooks install`.
+type HooksConfig struct {
+    // Require is RequireKey (default), RequireConventional or RequireBoth.
+    Require string `json:"require,omitempty"`
+    // VerifyKey makes the commit-msg hook check that the issue exists in Jira.
+    VerifyKey bool `json:"verify_key,omitempty"`
+}
+
+// SlugConfig controls how issue summaries are turned into branch name slugs.
+type SlugConfig struct {
+    // GermanUmlauts transliterates ä, ö and ü as ae, oe and ue instead of
+    // dropping the diaeresis.
+    GermanUmlauts bool `json:"german_umlauts,omitempty"`
+    // RemoveStopWords drops common English and Indonesian filler words, plus
+    // any listed in StopWords.
+    RemoveStopWords bool     `json:"remove_stop_words,omitempty"`
+    StopWords       []string `json:"stop_words,omitempty"`
+    // KeepUnicode keeps letters that have no ASCII transliteration (e.g.
+    // CJK) instead of dropping them.
+    KeepUnicode bool `json:"keep_unicode,omitempty"`
+}
+
+// TransitionRule updates the Jira issue after a jt lifecycle event, e.g.
+// {"on": "push", "to": "In Review"} or
+// {"on": "branch", "branch_type": "hotfix", "assign": "me"}.
+type TransitionRule struct {
+    On         string `json:"on"`
+    BranchType string `json:"branch_type,omitempty"`
+    To         string `json:"to,omitempty"`
+    Resolution string `json:"resolution,omitempty"`
+    // Assign is "me" or the account ID of the assignee.
+    Assign string `json:"assign,omitempty"`
+}
+
+// RulesFor returns the transition rules that apply to an event on a branch
+// of the given type, in the order they were declared.
+func (c *BranchConfig) RulesFor(event, branchType string) []TransitionRule {
+    var rules []TransitionRule
+    for _, rule := range c.Transitions {
+        if rule.On != event {
+            continue
+        }
+        if rule.BranchType != "" && rule.BranchType != branchType {
+            continue
+        }
+        rules = append(rules, rule)
+    }
+    return rules
 }
 
 // Add new functions to handle project-specific configs
@@ -23,39 +174,36 @@ func GetProjectConfigPath(projectPath string) (string, error) {
     if _, err := os.Stat(projectPath); err != nil {
         return "", fmt.Errorf("invalid project path: %v", err)
     }
-    return filepath.Join(projectPath, ".jt-config.json"), nil
+    return filepath.Join(projectPath, LocalConfigFile), nil
 }
 
+// LoadProjectBranchConfig returns the configuration of a project, merged
+// from all layers with Overrides applied. It fails when the project has
+// neither a team nor a local configuration file.
 func LoadProjectBranchConfig(projectPath string) (*BranchConfig, error) {
-    configPath, err := GetProjectConfigPath(projectPath)
-    if err != nil {
+    if _, err := GetProjectConfigPath(projectPath); err != nil {
         return nil, err
     }
-
-    data, err := os.ReadFile(configPath)
+    resolution, err := Resolve(projectPath, Overrides)
     if err != nil {
         return nil, err
     }
-
-    var config BranchConfig
-    if err := json.Unmarshal(data, &config); err != nil {
-        return nil, err
+    if !resolution.HasProjectFile() {
+        return nil, fmt.Errorf("no jt configuration in %s, run 'jt setup' first", projectPath)
     }
-
-    return &config, nil
+    return resolution.Config, nil
 }
 
-func SaveProjectBranchConfig(config *BranchConfig) error {
-    if config.ProjectPath == "" {
-        return fmt.Errorf("project path cannot be empty")
-    }
-
-    configPath, err := GetProjectConfigPath(config.ProjectPath)
+// SaveProjectSettings writes the local configuration file of a project.
+// Only the given settings are written, so that the file does not override
+// the team file for the others.
+func SaveProjectSettings(projectPath string, values map[string]interface{}) error {
+    configPath, err := GetProjectConfigPath(projectPath)
     if err != nil {
         return err
     }
 
-    data, err := json.MarshalIndent(config, "", "  ")
+    data, err := json.MarshalIndent(values, "", "  ")
     if err != nil {
         return err
     }
@@ -0,0 +1,74 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+)
+
+// GetEnvPath returns the path of the .env file with the Jira connection
+// settings of a profile.
+func GetEnvPath(profile string) (string, error) {
+    profileDir, err := GetProfilePath(profile)
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(profileDir, ".env"), nil
+}
+
+// UpdateEnvFile sets keys of a .env file in place, keeping the other lines
+// and their order. An empty value removes the key. The file is created when
+// it does not exist.
+func UpdateEnvFile(path string, values map[string]string) error {
+    data, err := os.ReadFile(path)
+    if err != nil && !os.IsNotExist(err) {
+        return err
+    }
+
+    var lines []string
+    done := make(map[string]bool)
+    for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+        key := envKey(line)
+        value, update := values[key]
+        switch {
+        case line == "" && len(data) == 0:
+            // Empty or missing file
+        case !update:
+            lines = append(lines, line)
+        case !done[key] && value != "":
+            lines = append(lines, key+"="+value)
+            done[key] = true
+        }
+    }
+
+    var added []string
+    for key, value := range values {
+        if !done[key] && value != "" {
+            added = append(added, key+"="+value)
+        }
+    }
+    // Map order is random, keep additions stable
+    sort.Strings(added)
+    lines = append(lines, added...)
+
+    content := ""
+    if len(lines) > 0 {
+        content = strings.Join(lines, "\n") + "\n"
+    }
+    return os.WriteFile(path, []byte(content), 0600)
+}
+
+// envKey returns the key of a KEY=value line, or "" for comments and
+// blank lines.
+func envKey(line string) string {
+    line = strings.TrimSpace(line)
+    if line == "" || strings.HasPrefix(line, "#") {
+        return ""
+    }
+    line = strings.TrimPrefix(line, "export ")
+    if i := strings.Index(line, "="); i > 0 {
+        return strings.TrimSpace(line[:i])
+    }
+    return ""
+}
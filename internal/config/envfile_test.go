@@ -0,0 +1,73 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestUpdateEnvFile(t *testing.T) {
+    tests := []struct {
+        name    string
+        initial string
+        values  map[string]string
+        want    string
+    }{
+        {
+            name:    "missing file",
+            initial: "-",
+            values:  map[string]string{"JIRA_DOMAIN": "acme.atlassian.net", "JIRA_AUTH": "basic"},
+            want:    "JIRA_AUTH=basic\nJIRA_DOMAIN=acme.atlassian.net\n",
+        },
+        {
+            name:    "update in place",
+            initial: "# Jira\nJIRA_DOMAIN=old.atlassian.net\n\nexport JIRA_EMAIL=dev@example.com\nJIRA_API_TOKEN=old\n",
+            values:  map[string]string{"JIRA_API_TOKEN": "new", "JIRA_DOMAIN": "acme.atlassian.net"},
+            want:    "# Jira\nJIRA_DOMAIN=acme.atlassian.net\n\nexport JIRA_EMAIL=dev@example.com\nJIRA_API_TOKEN=new\n",
+        },
+        {
+            name:    "empty value deletes",
+            initial: "JIRA_DOMAIN=acme.atlassian.net\nJIRA_API_TOKEN=old\nJIRA_EMAIL=dev@example.com\n",
+            values:  map[string]string{"JIRA_API_TOKEN": "", "JIRA_MISSING": ""},
+            want:    "JIRA_DOMAIN=acme.atlassian.net\nJIRA_EMAIL=dev@example.com\n",
+        },
+        {
+            name:    "new keys appended",
+            initial: "JIRA_DOMAIN=acme.atlassian.net\n",
+            values:  map[string]string{"JIRA_CREDENTIAL_STORE": "pass", "JIRA_AUTH": "basic"},
+            want:    "JIRA_DOMAIN=acme.atlassian.net\nJIRA_AUTH=basic\nJIRA_CREDENTIAL_STORE=pass\n",
+        },
+        {
+            name:    "duplicate key collapsed",
+            initial: "JIRA_API_TOKEN=one\nJIRA_DOMAIN=acme.atlassian.net\nJIRA_API_TOKEN=two\n",
+            values:  map[string]string{"JIRA_API_TOKEN": "new"},
+            want:    "JIRA_API_TOKEN=new\nJIRA_DOMAIN=acme.atlassian.net\n",
+        },
+        {
+            name:    "last key deleted",
+            initial: "JIRA_API_TOKEN=old\n",
+            values:  map[string]string{"JIRA_API_TOKEN": ""},
+            want:    "",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            path := filepath.Join(t.TempDir(), ".env")
+            if tt.initial != "-" {
+                if err := os.WriteFile(path, []byte(tt.initial), 0600); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            if err := UpdateEnvFile(path, tt.values); err != nil {
+                t.Fatalf("UpdateEnvFile: %v", err)
+            }
+            data, err := os.ReadFile(path)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if string(data) != tt.want {
+                t.Errorf("got:\n%s\nwant:\n%s", data, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,411 @@
+package config
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+
+    "gopkg.in/yaml.v3"
+)
+
+// Configuration layers, from lowest to highest precedence.
+const (
+    LayerDefault = "default"
+    LayerGlobal  = "global"
+    LayerTeam    = "team"
+    LayerLocal   = "local"
+    LayerEnv     = "env"
+    LayerFlag    = "flag"
+)
+
+// Project configuration files. The team file is committed, the local file
+// is created by `jt setup` and ignored by git.
+var (
+    TeamConfigFiles = []string{".jt.yaml", ".jt.json"}
+    LocalConfigFile = ".jt-config.json"
+)
+
+// Origin tells where a setting came from: its layer, and the file,
+// environment variable or flag that set it.
+type Origin struct {
+    Layer  string
+    Source string
+}
+
+// ResolveOptions selects the layers above the configuration files.
+type ResolveOptions struct {
+    // Getenv looks up the JT_* overrides. Nil skips the environment.
+    Getenv func(string) string
+    // Flags are overrides from the command line, by setting key.
+    Flags map[string]string
+}
+
+// Overrides are the environment and command line overrides applied by
+// LoadProjectBranchConfig.
+var Overrides = ResolveOptions{Getenv: os.Getenv, Flags: map[string]string{}}
+
+// Resolution is the configuration of a project merged from all layers.
+type Resolution struct {
+    Config *BranchConfig
+    // Values are the merged settings, as decoded from JSON or YAML.
+    Values map[string]interface{}
+    // Origins tells, by dotted key such as "finish.merge", where each
+    // setting in Values came from.
+    Origins map[string]Origin
+    // Layers are the layers that contributed settings, lowest first.
+    Layers []Origin
+    // Warnings report unknown settings in the configuration files.
+    Warnings []string
+}
+
+// HasProjectFile reports whether the project has a team or local
+// configuration file.
+func (r *Resolution) HasProjectFile() bool {
+    for _, layer := range r.Layers {
+        if layer.Layer == LayerTeam || layer.Layer == LayerLocal {
+            return true
+        }
+    }
+    return false
+}
+
+// defaultValues are the built-in settings. Empty settings mean the same,
+// they are listed so `jt config show` can explain them.
+func defaultValues() map[string]interface{} {
+    return map[string]interface{}{
+        "profile":     DefaultProfile,
+        "commit_mode": CommitModeStaged,
+        "finish": map[string]interface{}{
+            "merge":      MergeNoFF,
+            "tag_prefix": "v",
+        },
+        "hooks": map[string]interface{}{
+            "require": RequireKey,
+        },
+    }
+}
+
+// GetGlobalConfigPath returns the path of the configuration file shared by
+// all projects.
+func GetGlobalConfigPath() (string, error) {
+    configDir, err := GetConfigPath()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(configDir, "config"), nil
+}
+
+// Resolve merges the configuration layers of a project: the built-in
+// defaults, ~/.jira-tools/config, the committed .jt.yaml or .jt.json, the
+// local .jt-config.json, JT_* environment variables and command line flags.
+// Objects are merged key by key, other values replace the lower layers.
+// An empty projectPath resolves the layers outside a project.
+func Resolve(projectPath string, opts ResolveOptions) (*Resolution, error) {
+    r := &Resolution{
+        Values:  make(map[string]interface{}),
+        Origins: make(map[string]Origin),
+    }
+    r.add(Origin{LayerDefault, "built-in default"}, defaultValues())
+
+    globalPath, err := GetGlobalConfigPath()
+    if err != nil {
+        return nil, err
+    }
+    if err := r.addFile(LayerGlobal, globalPath); err != nil {
+        return nil, err
+    }
+
+    if projectPath != "" {
+        teamPath, err := teamConfigPath(projectPath)
+        if err != nil {
+            return nil, err
+        }
+        if teamPath != "" {
+            if err := r.addFile(LayerTeam, teamPath); err != nil {
+                return nil, err
+            }
+        }
+        if err := r.addFile(LayerLocal, filepath.Join(projectPath, LocalConfigFile)); err != nil {
+            return nil, err
+        }
+    }
+
+    if opts.Getenv != nil {
+        for _, s := range settings() {
+            name := EnvName(s.key)
+            value := opts.Getenv(name)
+            if value == "" || !s.overridable() {
+                continue
+            }
+            parsed, err := s.parse(value)
+            if err != nil {
+                return nil, fmt.Errorf("invalid %s: %v", name, err)
+            }
+            r.add(Origin{LayerEnv, name}, nested(s.key, parsed))
+        }
+    }
+
+    keys := make([]string, 0, len(opts.Flags))
+    for key := range opts.Flags {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    for _, key := range keys {
+        s, ok := lookupSetting(key)
+        if !ok {
+            return nil, fmt.Errorf("unknown setting %q", key)
+        }
+        if !s.overridable() {
+            return nil, fmt.Errorf("%s cannot be set on the command line, use a configuration file", key)
+        }
+        parsed, err := s.parse(opts.Flags[key])
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %v", key, err)
+        }
+        r.add(Origin{LayerFlag, "command line"}, nested(key, parsed))
+    }
+
+    data, err := json.Marshal(r.Values)
+    if err != nil {
+        return nil, err
+    }
+    var config BranchConfig
+    if err := json.Unmarshal(data, &config); err != nil {
+        return nil, fmt.Errorf("invalid configuration: %v", err)
+    }
+    config.ProjectPath = projectPath
+    r.Config = &config
+    return r, nil
+}
+
+// teamConfigPath returns the team configuration file of a project, or ""
+// when there is none.
+func teamConfigPath(projectPath string) (string, error) {
+    var teamPath string
+    for _, name := range TeamConfigFiles {
+        path := filepath.Join(projectPath, name)
+        if _, err := os.Stat(path); err != nil {
+            continue
+        }
+        if teamPath != "" {
+            return "", fmt.Errorf("both %s and %s exist, keep only one", filepath.Base(teamPath), name)
+        }
+        teamPath = path
+    }
+    return teamPath, nil
+}
+
+// TeamSettings returns the settings of the team configuration file of a
+// project and its path. Both are empty when the project has no team file.
+func TeamSettings(projectPath string) (map[string]interface{}, string, error) {
+    path, err := teamConfigPath(projectPath)
+    if err != nil || path == "" {
+        return nil, "", err
+    }
+    r := &Resolution{
+        Values:  make(map[string]interface{}),
+        Origins: make(map[string]Origin),
+    }
+    if err := r.addFile(LayerTeam, path); err != nil {
+        return nil, "", err
+    }
+    return r.Values, path, nil
+}
+
+// addFile adds the settings of a JSON or YAML file, when it exists.
+func (r *Resolution) addFile(layer, path string) error {
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    values := make(map[string]interface{})
+    if strings.HasSuffix(path, ".json") {
+        err = json.Unmarshal(data, &values)
+    } else {
+        // YAML is a superset of JSON, so the global file can be either
+        err = yaml.Unmarshal(data, &values)
+    }
+    if err != nil {
+        return fmt.Errorf("invalid configuration file %s: %v", path, err)
+    }
+    // The project path is where the file is, not a setting
+    delete(values, "project_path")
+
+    // Check the types here, where the error can name the file
+    check, err := json.Marshal(values)
+    if err != nil {
+        return fmt.Errorf("invalid configuration file %s: %v", path, err)
+    }
+    if err := json.Unmarshal(check, &BranchConfig{}); err != nil {
+        return fmt.Errorf("invalid configuration file %s: %v", path, err)
+    }
+    for _, key := range unknownKeys(values, "") {
+        r.Warnings = append(r.Warnings, fmt.Sprintf("unknown setting %q in %s", key, path))
+    }
+
+    r.add(Origin{layer, path}, values)
+    return nil
+}
+
+func (r *Resolution) add(origin Origin, values map[string]interface{}) {
+    r.Layers = append(r.Layers, origin)
+    merge(r.Values, values, "", origin, r.Origins)
+}
+
+// merge copies src over dst, merging objects key by key, and records the
+// origin of every value copied.
+func merge(dst, src map[string]interface{}, prefix string, origin Origin, origins map[string]Origin) {
+    for key, value := range src {
+        path := prefix + key
+        if object, ok := value.(map[string]interface{}); ok {
+            target, ok := dst[key].(map[string]interface{})
+            if !ok {
+                target = make(map[string]interface{})
+                dst[key] = target
+                delete(origins, path)
+            }
+            merge(target, object, path+".", origin, origins)
+            continue
+        }
+        dst[key] = value
+        for other := range origins {
+            if strings.HasPrefix(other, path+".") {
+                delete(origins, other)
+            }
+        }
+        origins[path] = origin
+    }
+}
+
+// nested turns a dotted key and its value into nested objects.
+func nested(key string, value interface{}) map[string]interface{} {
+    parts := strings.Split(key, ".")
+    values := map[string]interface{}{parts[len(parts)-1]: value}
+    for i := len(parts) - 2; i >= 0; i-- {
+        values = map[string]interface{}{parts[i]: values}
+    }
+    return values
+}
+
+// EnvName returns the environment variable overriding a setting, e.g.
+// JT_FINISH_MERGE for finish.merge.
+func EnvName(key string) string {
+    return "JT_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// setting is a key of BranchConfig, with nested structs flattened into
+// dotted keys such as "finish.merge".
+type setting struct {
+    key string
+    typ reflect.Type
+}
+
+func settings() []setting {
+    var all []setting
+    collectSettings(reflect.TypeOf(BranchConfig{}), "", &all)
+    return all
+}
+
+func collectSettings(t reflect.Type, prefix string, all *[]setting) {
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        name := strings.Split(field.Tag.Get("json"), ",")[0]
+        if name == "" || name == "-" || name == "project_path" {
+            continue
+        }
+        if field.Type.Kind() == reflect.Struct {
+            collectSettings(field.Type, prefix+name+".", all)
+            continue
+        }
+        *all = append(*all, setting{key: prefix + name, typ: field.Type})
+    }
+}
+
+func lookupSetting(key string) (setting, bool) {
+    for _, s := range settings() {
+        if s.key == key {
+            return s, true
+        }
+    }
+    return setting{}, false
+}
+
+// overridable reports whether the setting can be given as a single string,
+// in the environment or on the command line.
+func (s setting) overridable() bool {
+    switch s.typ.Kind() {
+    case reflect.String, reflect.Bool, reflect.Int:
+        return true
+    case reflect.Ptr:
+        return s.typ.Elem().Kind() == reflect.String
+    case reflect.Slice:
+        return s.typ.Elem().Kind() == reflect.String
+    }
+    return false
+}
+
+// parse converts an override to the type of the setting. Lists are comma
+// separated.
+func (s setting) parse(value string) (interface{}, error) {
+    switch s.typ.Kind() {
+    case reflect.Bool:
+        parsed, err := strconv.ParseBool(value)
+        if err != nil {
+            return nil, fmt.Errorf("expected true or false, got %q", value)
+        }
+        return parsed, nil
+    case reflect.Int:
+        parsed, err := strconv.Atoi(value)
+        if err != nil {
+            return nil, fmt.Errorf("expected a number, got %q", value)
+        }
+        return parsed, nil
+    case reflect.Slice:
+        var items []interface{}
+        for _, item := range strings.Split(value, ",") {
+            if item = strings.TrimSpace(item); item != "" {
+                items = append(items, item)
+            }
+        }
+        return items, nil
+    }
+    return value, nil
+}
+
+// unknownKeys returns the keys of values that are not settings. The
+// contents of maps and lists, such as branch_templates, are not checked.
+func unknownKeys(values map[string]interface{}, prefix string) []string {
+    var unknown []string
+    for key, value := range values {
+        path := prefix + key
+        if _, ok := lookupSetting(path); ok {
+            continue
+        }
+        object, isObject := value.(map[string]interface{})
+        if isObject && isSettingGroup(path) {
+            unknown = append(unknown, unknownKeys(object, path+".")...)
+            continue
+        }
+        unknown = append(unknown, path)
+    }
+    sort.Strings(unknown)
+    return unknown
+}
+
+func isSettingGroup(path string) bool {
+    for _, s := range settings() {
+        if strings.HasPrefix(s.key, path+".") {
+            return true
+        }
+    }
+    return false
+}
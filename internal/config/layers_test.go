@@ -0,0 +1,222 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// setHome points the home directory, and so ~/.jira-tools/config, at a
+// temporary directory for the duration of the test.
+func setHome(t *testing.T) string {
+    t.Helper()
+    home := t.TempDir()
+    old, ok := os.LookupEnv("HOME")
+    os.Setenv("HOME", home)
+    t.Cleanup(func() {
+        if ok {
+            os.Setenv("HOME", old)
+        } else {
+            os.Unsetenv("HOME")
+        }
+    })
+    return home
+}
+
+func writeConfigFile(t *testing.T, path, content string) {
+    t.Helper()
+    if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestResolvePrecedence(t *testing.T) {
+    tests := []struct {
+        name   string
+        global string
+        team   string
+        local  string
+        env    string
+        flag   string
+        want   string
+        layer  string
+    }{
+        {name: "default", want: "v", layer: LayerDefault},
+        {name: "global", global: "global-", want: "global-", layer: LayerGlobal},
+        {name: "team", global: "global-", team: "team-", want: "team-", layer: LayerTeam},
+        {name: "local", global: "global-", team: "team-", local: "local-", want: "local-", layer: LayerLocal},
+        {name: "env", team: "team-", local: "local-", env: "env-", want: "env-", layer: LayerEnv},
+        {name: "flag", team: "team-", local: "local-", env: "env-", flag: "flag-", want: "flag-", layer: LayerFlag},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            home := setHome(t)
+            project := t.TempDir()
+            if tt.global != "" {
+                writeConfigFile(t, filepath.Join(home, ".jira-tools", "config"), "finish:\n  tag_prefix: "+tt.global+"\n")
+            }
+            if tt.team != "" {
+                writeConfigFile(t, filepath.Join(project, ".jt.yaml"), "finish:\n  tag_prefix: "+tt.team+"\n")
+            }
+            if tt.local != "" {
+                writeConfigFile(t, filepath.Join(project, LocalConfigFile), `{"finish":{"tag_prefix":"`+tt.local+`"}}`)
+            }
+            opts := ResolveOptions{
+                Getenv: func(name string) string {
+                    if name == "JT_FINISH_TAG_PREFIX" {
+                        return tt.env
+                    }
+                    return ""
+                },
+                Flags: map[string]string{},
+            }
+            if tt.flag != "" {
+                opts.Flags["finish.tag_prefix"] = tt.flag
+            }
+
+            r, err := Resolve(project, opts)
+            if err != nil {
+                t.Fatalf("Resolve: %v", err)
+            }
+            if prefix := r.Config.Finish.TagPrefix; prefix == nil || *prefix != tt.want {
+                t.Errorf("tag prefix = %v, want %q", prefix, tt.want)
+            }
+            if origin := r.Origins["finish.tag_prefix"]; origin.Layer != tt.layer {
+                t.Errorf("origin = %+v, want the %s layer", origin, tt.layer)
+            }
+            // The other settings of the object keep their origin
+            if origin := r.Origins["finish.merge"]; origin.Layer != LayerDefault {
+                t.Errorf("finish.merge origin = %+v, want the default layer", origin)
+            }
+        })
+    }
+}
+
+func TestResolveOrigins(t *testing.T) {
+    tests := []struct {
+        name    string
+        global  string
+        team    string
+        env     map[string]string
+        origins map[string]string
+        missing []string
+    }{
+        {
+            name:    "objects merged key by key",
+            global:  "finish:\n  merge: squash\n",
+            team:    "finish:\n  tag_prefix: release-\n",
+            origins: map[string]string{"finish.merge": LayerGlobal, "finish.tag_prefix": LayerTeam},
+        },
+        {
+            name:    "object replaced by a value",
+            global:  "finish:\n  merge: squash\n",
+            team:    "finish: null\n",
+            origins: map[string]string{"finish": LayerTeam},
+            missing: []string{"finish.merge", "finish.tag_prefix"},
+        },
+        {
+            name:    "value replaced by an object",
+            team:    "finish: null\n",
+            env:     map[string]string{"JT_FINISH_MERGE": "ff"},
+            origins: map[string]string{"finish.merge": LayerEnv},
+            missing: []string{"finish", "finish.tag_prefix"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            home := setHome(t)
+            project := t.TempDir()
+            if tt.global != "" {
+                writeConfigFile(t, filepath.Join(home, ".jira-tools", "config"), tt.global)
+            }
+            writeConfigFile(t, filepath.Join(project, ".jt.yaml"), tt.team)
+
+            r, err := Resolve(project, ResolveOptions{Getenv: func(name string) string { return tt.env[name] }})
+            if err != nil {
+                t.Fatalf("Resolve: %v", err)
+            }
+            for key, layer := range tt.origins {
+                if origin, ok := r.Origins[key]; !ok || origin.Layer != layer {
+                    t.Errorf("origin of %s = %+v, want the %s layer", key, origin, layer)
+                }
+            }
+            for _, key := range tt.missing {
+                if origin, ok := r.Origins[key]; ok {
+                    t.Errorf("stale origin %s = %+v", key, origin)
+                }
+            }
+            // Every origin names a value that is still there
+            for key := range r.Origins {
+                if !hasValue(r.Values, key) {
+                    t.Errorf("origin %s has no value", key)
+                }
+            }
+        })
+    }
+}
+
+func TestResolveTeamFiles(t *testing.T) {
+    tests := []struct {
+        name   string
+        files  map[string]string
+        want   string
+        errMsg string
+    }{
+        {
+            name:  "yaml",
+            files: map[string]string{".jt.yaml": "commit_mode: all\n"},
+            want:  "all",
+        },
+        {
+            name:  "json",
+            files: map[string]string{".jt.json": `{"commit_mode":"all"}`},
+            want:  "all",
+        },
+        {
+            name:   "both",
+            files:  map[string]string{".jt.yaml": "commit_mode: all\n", ".jt.json": `{"commit_mode":"all"}`},
+            errMsg: "both .jt.yaml and .jt.json exist",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            setHome(t)
+            project := t.TempDir()
+            for name, content := range tt.files {
+                writeConfigFile(t, filepath.Join(project, name), content)
+            }
+
+            r, err := Resolve(project, ResolveOptions{})
+            _, _, teamErr := TeamSettings(project)
+            if tt.errMsg != "" {
+                if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+                    t.Errorf("Resolve error = %v, want %q", err, tt.errMsg)
+                }
+                if teamErr == nil || !strings.Contains(teamErr.Error(), tt.errMsg) {
+                    t.Errorf("TeamSettings error = %v, want %q", teamErr, tt.errMsg)
+                }
+                return
+            }
+            if err != nil || teamErr != nil {
+                t.Fatalf("Resolve: %v, TeamSettings: %v", err, teamErr)
+            }
+            if r.Config.CommitMode != tt.want || r.Origins["commit_mode"].Layer != LayerTeam {
+                t.Errorf("commit_mode = %q from %+v, want %q from the team file", r.Config.CommitMode, r.Origins["commit_mode"], tt.want)
+            }
+        })
+    }
+}
+
+func hasValue(values map[string]interface{}, key string) bool {
+    parts := strings.SplitN(key, ".", 2)
+    value, ok := values[parts[0]]
+    if !ok || len(parts) == 1 {
+        return ok
+    }
+    object, ok := value.(map[string]interface{})
+    return ok && hasValue(object, parts[1])
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+)
+
+// DefaultProfile is the Jira profile used when none is selected. Its
+// settings are in ~/.jira-tools/.env.
+const DefaultProfile = "default"
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ValidateProfileName checks that a profile name can be used as a directory
+// name.
+func ValidateProfileName(profile string) error {
+    if !profileNamePattern.MatchString(profile) {
+        return fmt.Errorf("invalid profile name %q, use letters, digits, '.', '_' and '-'", profile)
+    }
+    return nil
+}
+
+// GetProfilePath returns the directory with the settings of a profile:
+// ~/.jira-tools for the default profile and ~/.jira-tools/profiles/<name>
+// for the others. Unlike GetConfigPath it does not create the directory.
+func GetProfilePath(profile string) (string, error) {
+    configDir, err := GetConfigPath()
+    if err != nil {
+        return "", err
+    }
+    if profile == "" || profile == DefaultProfile {
+        return configDir, nil
+    }
+    if err := ValidateProfileName(profile); err != nil {
+        return "", err
+    }
+    return filepath.Join(configDir, "profiles", profile), nil
+}
+
+// ListProfiles returns the profiles that have a .env file, the default
+// profile first and the others sorted by name.
+func ListProfiles() ([]string, error) {
+    var profiles []string
+    envPath, err := GetEnvPath(DefaultProfile)
+    if err != nil {
+        return nil, err
+    }
+    if _, err := os.Stat(envPath); err == nil {
+        profiles = append(profiles, DefaultProfile)
+    }
+
+    configDir, err := GetConfigPath()
+    if err != nil {
+        return nil, err
+    }
+    entries, err := os.ReadDir(filepath.Join(configDir, "profiles"))
+    if err != nil && !os.IsNotExist(err) {
+        return nil, err
+    }
+    var named []string
+    for _, entry := range entries {
+        if !entry.IsDir() || ValidateProfileName(entry.Name()) != nil {
+            continue
+        }
+        if _, err := os.Stat(filepath.Join(configDir, "profiles", entry.Name(), ".env")); err == nil {
+            named = append(named, entry.Name())
+        }
+    }
+    sort.Strings(named)
+    return append(profiles, named...), nil
+}
+
+// ProjectProfile returns the profile a project uses.
+func (c *BranchConfig) ProjectProfile() string {
+    if c.Profile == "" {
+        return DefaultProfile
+    }
+    return c.Profile
+}
+
+func reposPath() (string, error) {
+    configDir, err := GetConfigPath()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(configDir, "repos.json"), nil
+}
+
+// LoadKnownRepos returns the project paths jt has been used in, so their
+// profiles can be listed.
+func LoadKnownRepos() ([]string, error) {
+    path, err := reposPath()
+    if err != nil {
+        return nil, err
+    }
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    var repos []string
+    if err := json.Unmarshal(data, &repos); err != nil {
+        return nil, fmt.Errorf("invalid repository list %s: %v", path, err)
+    }
+    return repos, nil
+}
+
+// RecordRepo adds a project path to the known repositories.
+func RecordRepo(projectPath string) error {
+    repos, err := LoadKnownRepos()
+    if err != nil {
+        return err
+    }
+    for _, repo := range repos {
+        if repo == projectPath {
+            return nil
+        }
+    }
+    repos = append(repos, projectPath)
+    sort.Strings(repos)
+
+    data, err := json.MarshalIndent(repos, "", "  ")
+    if err != nil {
+        return err
+    }
+    path, err := reposPath()
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,35 @@
+package credentials
+
+import (
+    "jira-tools/internal/config"
+
+    "github.com/joho/godotenv"
+)
+
+// envStore keeps secrets in plain text in the .env file, as jt did before
+// the other stores existed.
+type envStore struct {
+    path string
+}
+
+func (envStore) Kind() string { return Env }
+func (envStore) Name() string { return Describe(Env) }
+
+func (s envStore) Get(key string) (string, error) {
+    values, err := godotenv.Read(s.path)
+    if err != nil {
+        return "", err
+    }
+    if value := values[key]; value != "" {
+        return value, nil
+    }
+    return "", ErrNotFound
+}
+
+func (s envStore) Set(key, value string) error {
+    return config.UpdateEnvFile(s.path, map[string]string{key: value})
+}
+
+func (s envStore) Delete(key string) error {
+    return config.UpdateEnvFile(s.path, map[string]string{key: ""})
+}
@@ -0,0 +1,203 @@
+package credentials
+
+import (
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/rand"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+
+    "golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters recommended for interactive logins.
+const (
+    scryptN      = 1 << 15
+    scryptR      = 8
+    scryptP      = 1
+    scryptKeyLen = 32
+)
+
+// encryptedFile is the JSON envelope of the file store. The secrets are
+// AES-256-GCM encrypted with a key derived from the passphrase by scrypt.
+type encryptedFile struct {
+    Version    int    `json:"version"`
+    KDF        string `json:"kdf"`
+    N          int    `json:"n"`
+    R          int    `json:"r"`
+    P          int    `json:"p"`
+    Salt       []byte `json:"salt"`
+    Nonce      []byte `json:"nonce"`
+    Ciphertext []byte `json:"ciphertext"`
+}
+
+// fileStore keeps secrets in a passphrase protected file. The passphrase
+// is asked once per process.
+type fileStore struct {
+    path       string
+    passphrase func(create bool) (string, error)
+
+    phrase  string
+    secrets map[string]string
+}
+
+func (s *fileStore) Kind() string { return File }
+func (s *fileStore) Name() string { return Describe(File) }
+
+func (s *fileStore) Get(key string) (string, error) {
+    if err := s.load(); err != nil {
+        return "", err
+    }
+    value, ok := s.secrets[key]
+    if !ok {
+        return "", ErrNotFound
+    }
+    return value, nil
+}
+
+func (s *fileStore) Set(key, value string) error {
+    if err := s.load(); err != nil {
+        return err
+    }
+    s.secrets[key] = value
+    return s.save()
+}
+
+func (s *fileStore) Delete(key string) error {
+    if _, err := os.Stat(s.path); os.IsNotExist(err) {
+        return nil
+    }
+    if err := s.load(); err != nil {
+        return err
+    }
+    if _, ok := s.secrets[key]; !ok {
+        return nil
+    }
+    delete(s.secrets, key)
+    return s.save()
+}
+
+// load decrypts the file, or starts an empty one when it does not exist.
+func (s *fileStore) load() error {
+    if s.secrets != nil {
+        return nil
+    }
+
+    data, err := os.ReadFile(s.path)
+    if os.IsNotExist(err) {
+        if err := s.askPassphrase(true); err != nil {
+            return err
+        }
+        s.secrets = make(map[string]string)
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    var file encryptedFile
+    if err := json.Unmarshal(data, &file); err != nil {
+        return fmt.Errorf("invalid credentials file %s: %v", s.path, err)
+    }
+    if file.Version != 1 || file.KDF != "scrypt" {
+        return fmt.Errorf("unsupported credentials file %s", s.path)
+    }
+    if err := s.askPassphrase(false); err != nil {
+        return err
+    }
+
+    gcm, err := newGCM(s.phrase, file.Salt, file.N, file.R, file.P)
+    if err != nil {
+        return err
+    }
+    plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+    if err != nil {
+        s.phrase = ""
+        return fmt.Errorf("wrong passphrase for %s", s.path)
+    }
+
+    secrets := make(map[string]string)
+    if err := json.Unmarshal(plaintext, &secrets); err != nil {
+        return fmt.Errorf("invalid credentials file %s: %v", s.path, err)
+    }
+    s.secrets = secrets
+    return nil
+}
+
+// save encrypts the secrets with a fresh salt and nonce and replaces the
+// file atomically.
+func (s *fileStore) save() error {
+    plaintext, err := json.Marshal(s.secrets)
+    if err != nil {
+        return err
+    }
+
+    file := encryptedFile{Version: 1, KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP}
+    file.Salt = make([]byte, 16)
+    if _, err := rand.Read(file.Salt); err != nil {
+        return err
+    }
+    gcm, err := newGCM(s.phrase, file.Salt, file.N, file.R, file.P)
+    if err != nil {
+        return err
+    }
+    file.Nonce = make([]byte, gcm.NonceSize())
+    if _, err := rand.Read(file.Nonce); err != nil {
+        return err
+    }
+    file.Ciphertext = gcm.Seal(nil, file.Nonce, plaintext, nil)
+
+    data, err := json.MarshalIndent(file, "", "  ")
+    if err != nil {
+        return err
+    }
+    tmp, err := os.CreateTemp(filepath.Dir(s.path), ".credentials-*")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(tmp.Name())
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+    if err := os.Chmod(tmp.Name(), 0600); err != nil {
+        return err
+    }
+    return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *fileStore) askPassphrase(create bool) error {
+    if s.phrase != "" {
+        return nil
+    }
+    if s.passphrase == nil {
+        return errors.New("no passphrase for the encrypted credentials file")
+    }
+    passphrase, err := s.passphrase(create)
+    if err != nil {
+        return err
+    }
+    if passphrase == "" {
+        return errors.New("the passphrase cannot be empty")
+    }
+    s.phrase = passphrase
+    return nil
+}
+
+func newGCM(passphrase string, salt []byte, n, r, p int) (cipher.AEAD, error) {
+    key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, scryptKeyLen)
+    if err != nil {
+        return nil, err
+    }
+    block, err := aes.NewCipher(key)
+    if err != nil {
+        return nil, err
+    }
+    return cipher.NewGCM(block)
+}
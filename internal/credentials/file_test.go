@@ -0,0 +1,120 @@
+package credentials
+
+import (
+    "bytes"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func newTestFileStore(path, passphrase string) *fileStore {
+    return &fileStore{path: path, passphrase: func(bool) (string, error) { return passphrase, nil }}
+}
+
+func readEncryptedFile(t *testing.T, path string) encryptedFile {
+    t.Helper()
+    data, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var file encryptedFile
+    if err := json.Unmarshal(data, &file); err != nil {
+        t.Fatal(err)
+    }
+    return file
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "credentials.enc")
+    store := newTestFileStore(path, "correct horse")
+    if err := store.Set("JIRA_API_TOKEN", "secret-token"); err != nil {
+        t.Fatalf("Set: %v", err)
+    }
+    if err := store.Set("client/JIRA_API_TOKEN", "client-token"); err != nil {
+        t.Fatalf("Set: %v", err)
+    }
+
+    data, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if bytes.Contains(data, []byte("secret-token")) {
+        t.Error("the file contains the secret in plain text")
+    }
+    info, err := os.Stat(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if info.Mode().Perm() != 0600 {
+        t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+    }
+
+    // A new process asks for the passphrase again
+    reopened := newTestFileStore(path, "correct horse")
+    for key, want := range map[string]string{"JIRA_API_TOKEN": "secret-token", "client/JIRA_API_TOKEN": "client-token"} {
+        if got, err := reopened.Get(key); err != nil || got != want {
+            t.Errorf("Get(%s) = %q, %v, want %q", key, got, err, want)
+        }
+    }
+    if _, err := reopened.Get("JIRA_OAUTH_TOKEN"); err != ErrNotFound {
+        t.Errorf("Get of a missing key error = %v, want ErrNotFound", err)
+    }
+
+    if err := reopened.Delete("JIRA_API_TOKEN"); err != nil {
+        t.Fatalf("Delete: %v", err)
+    }
+    if _, err := newTestFileStore(path, "correct horse").Get("JIRA_API_TOKEN"); err != ErrNotFound {
+        t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+    }
+}
+
+func TestFileStoreErrors(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "credentials.enc")
+    if err := newTestFileStore(path, "correct horse").Set("JIRA_API_TOKEN", "secret-token"); err != nil {
+        t.Fatalf("Set: %v", err)
+    }
+
+    _, err := newTestFileStore(path, "wrong").Get("JIRA_API_TOKEN")
+    if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+        t.Errorf("Get with a wrong passphrase error = %v", err)
+    }
+
+    file := readEncryptedFile(t, path)
+    file.Ciphertext[0] ^= 0xff
+    data, err := json.Marshal(file)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(path, data, 0600); err != nil {
+        t.Fatal(err)
+    }
+    // GCM authenticates the ciphertext, so tampering reads as a bad passphrase
+    if _, err := newTestFileStore(path, "correct horse").Get("JIRA_API_TOKEN"); err == nil {
+        t.Error("Get of a tampered file succeeded")
+    }
+}
+
+func TestFileStoreFreshSaltAndNonce(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "credentials.enc")
+    store := newTestFileStore(path, "correct horse")
+    if err := store.Set("JIRA_API_TOKEN", "secret-token"); err != nil {
+        t.Fatalf("Set: %v", err)
+    }
+    first := readEncryptedFile(t, path)
+    if err := store.Set("JIRA_API_TOKEN", "secret-token"); err != nil {
+        t.Fatalf("Set: %v", err)
+    }
+    second := readEncryptedFile(t, path)
+
+    if bytes.Equal(first.Salt, second.Salt) {
+        t.Error("the salt was reused")
+    }
+    if bytes.Equal(first.Nonce, second.Nonce) {
+        t.Error("the nonce was reused")
+    }
+    if bytes.Equal(first.Ciphertext, second.Ciphertext) {
+        t.Error("saving the same secrets gave the same ciphertext")
+    }
+}
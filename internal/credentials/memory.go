@@ -0,0 +1,33 @@
+package credentials
+
+// MemoryStore keeps secrets in memory. It stands in for the real stores
+// in tests.
+type MemoryStore struct {
+    Secrets map[string]string
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+    return &MemoryStore{Secrets: make(map[string]string)}
+}
+
+func (s *MemoryStore) Kind() string { return "memory" }
+func (s *MemoryStore) Name() string { return "memory" }
+
+func (s *MemoryStore) Get(key string) (string, error) {
+    value, ok := s.Secrets[key]
+    if !ok {
+        return "", ErrNotFound
+    }
+    return value, nil
+}
+
+func (s *MemoryStore) Set(key, value string) error {
+    s.Secrets[key] = value
+    return nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+    delete(s.Secrets, key)
+    return nil
+}
@@ -0,0 +1,50 @@
+package credentials
+
+import (
+    "bytes"
+    "fmt"
+    "os/exec"
+    "strings"
+)
+
+// passStore keeps secrets in pass, the standard Unix password manager,
+// under jira-tools/<key>.
+type passStore struct{}
+
+func (passStore) Kind() string { return Pass }
+func (passStore) Name() string { return Describe(Pass) }
+
+func passName(key string) string {
+    return Service + "/" + key
+}
+
+func (passStore) Get(key string) (string, error) {
+    var stdout, stderr bytes.Buffer
+    cmd := exec.Command("pass", "show", passName(key))
+    cmd.Stdout, cmd.Stderr = &stdout, &stderr
+    if err := cmd.Run(); err != nil {
+        if strings.Contains(stderr.String(), "is not in the password store") {
+            return "", ErrNotFound
+        }
+        return "", fmt.Errorf("pass show failed: %s", commandError(stderr.Bytes(), err))
+    }
+    // pass stores the secret on the first line
+    return strings.SplitN(stdout.String(), "\n", 2)[0], nil
+}
+
+func (passStore) Set(key, value string) error {
+    cmd := exec.Command("pass", "insert", "--multiline", "--force", passName(key))
+    cmd.Stdin = strings.NewReader(value + "\n")
+    if output, err := cmd.CombinedOutput(); err != nil {
+        return fmt.Errorf("pass insert failed: %s", commandError(output, err))
+    }
+    return nil
+}
+
+func (passStore) Delete(key string) error {
+    output, err := exec.Command("pass", "rm", "--force", passName(key)).CombinedOutput()
+    if err != nil && !strings.Contains(string(output), "is not in the password store") {
+        return fmt.Errorf("pass rm failed: %s", commandError(output, err))
+    }
+    return nil
+}
@@ -0,0 +1,46 @@
+package credentials
+
+import (
+    "bytes"
+    "fmt"
+    "os/exec"
+    "strings"
+)
+
+// secretServiceStore keeps secrets in the desktop keyring (GNOME Keyring,
+// KWallet) through the Secret Service D-Bus API, using libsecret's
+// secret-tool.
+type secretServiceStore struct{}
+
+func (secretServiceStore) Kind() string { return SecretService }
+func (secretServiceStore) Name() string { return Describe(SecretService) }
+
+func (secretServiceStore) Get(key string) (string, error) {
+    var stdout, stderr bytes.Buffer
+    cmd := exec.Command("secret-tool", "lookup", "service", Service, "key", key)
+    cmd.Stdout, cmd.Stderr = &stdout, &stderr
+    if err := cmd.Run(); err != nil {
+        // secret-tool exits with 1 and no output when nothing matches
+        if _, ok := err.(*exec.ExitError); ok && stderr.Len() == 0 {
+            return "", ErrNotFound
+        }
+        return "", fmt.Errorf("secret-tool lookup failed: %s", commandError(stderr.Bytes(), err))
+    }
+    return stdout.String(), nil
+}
+
+func (secretServiceStore) Set(key, value string) error {
+    cmd := exec.Command("secret-tool", "store", "--label", Service+" "+key, "service", Service, "key", key)
+    cmd.Stdin = strings.NewReader(value)
+    if output, err := cmd.CombinedOutput(); err != nil {
+        return fmt.Errorf("secret-tool store failed: %s", commandError(output, err))
+    }
+    return nil
+}
+
+func (secretServiceStore) Delete(key string) error {
+    if output, err := exec.Command("secret-tool", "clear", "service", Service, "key", key).CombinedOutput(); err != nil {
+        return fmt.Errorf("secret-tool clear failed: %s", commandError(output, err))
+    }
+    return nil
+}
@@ -0,0 +1,147 @@
+package credentials
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+
+    "jira-tools/internal/config"
+)
+
+// Store kinds, in order of preference.
+const (
+    SecretService = "secret-service"
+    Pass          = "pass"
+    File          = "file"
+    Env           = "env"
+)
+
+// Service namespaces the secrets of jt in shared stores.
+const Service = "jira-tools"
+
+// ErrNotFound is returned by Get when a store has no secret for a key.
+var ErrNotFound = errors.New("credential not found")
+
+// Store keeps secrets such as the Jira API token, by the name of the
+// environment variable they replace.
+type Store interface {
+    // Kind is one of the store kinds, Name describes the store to users.
+    Kind() string
+    Name() string
+    Get(key string) (string, error)
+    Set(key, value string) error
+    // Delete removes a secret. Deleting a missing secret is not an error.
+    Delete(key string) error
+}
+
+// Options configures the stores.
+type Options struct {
+    // EnvPath is the .env file of the env store.
+    EnvPath string
+    // FilePath is the encrypted file of the file store.
+    FilePath string
+    // Passphrase returns the passphrase of the encrypted file. create is set
+    // when the file does not exist yet, so the passphrase can be confirmed.
+    Passphrase func(create bool) (string, error)
+    // Profile namespaces the secrets of a named Jira profile in the stores
+    // shared by all profiles. Empty means the default profile.
+    Profile string
+}
+
+// Kinds lists the store kinds in order of preference.
+func Kinds() []string {
+    return []string{SecretService, Pass, File, Env}
+}
+
+// New returns the store of the given kind.
+func New(kind string, opts Options) (Store, error) {
+    var store Store
+    switch kind {
+    case SecretService:
+        store = secretServiceStore{}
+    case Pass:
+        store = passStore{}
+    case File:
+        if opts.FilePath == "" {
+            return nil, fmt.Errorf("no path for the encrypted credentials file")
+        }
+        store = &fileStore{path: opts.FilePath, passphrase: opts.Passphrase}
+    case Env:
+        if opts.EnvPath == "" {
+            return nil, fmt.Errorf("no path for the .env file")
+        }
+        // Every profile has its own .env file
+        return envStore{path: opts.EnvPath}, nil
+    default:
+        return nil, fmt.Errorf("unknown credential store %q, expected %s", kind, strings.Join(Kinds(), ", "))
+    }
+    if opts.Profile == "" || opts.Profile == config.DefaultProfile {
+        return store, nil
+    }
+    return profileStore{Store: store, prefix: opts.Profile + "/"}, nil
+}
+
+// profileStore prefixes the keys of a named profile, e.g. client/JIRA_API_TOKEN,
+// so that profiles can share a store.
+type profileStore struct {
+    Store
+    prefix string
+}
+
+func (s profileStore) Get(key string) (string, error) { return s.Store.Get(s.prefix + key) }
+func (s profileStore) Set(key, value string) error    { return s.Store.Set(s.prefix+key, value) }
+func (s profileStore) Delete(key string) error        { return s.Store.Delete(s.prefix + key) }
+
+// Available reports whether a store kind can be used on this machine. The
+// encrypted file and env stores are always available.
+func Available(kind string) bool {
+    switch kind {
+    case SecretService:
+        _, err := exec.LookPath("secret-tool")
+        return err == nil && os.Getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+    case Pass:
+        if _, err := exec.LookPath("pass"); err != nil {
+            return false
+        }
+        dir := os.Getenv("PASSWORD_STORE_DIR")
+        if dir == "" {
+            home, err := os.UserHomeDir()
+            if err != nil {
+                return false
+            }
+            dir = filepath.Join(home, ".password-store")
+        }
+        _, err := os.Stat(filepath.Join(dir, ".gpg-id"))
+        return err == nil
+    case File, Env:
+        return true
+    }
+    return false
+}
+
+// Describe returns the user-facing name of a store kind.
+func Describe(kind string) string {
+    switch kind {
+    case SecretService:
+        return "Secret Service keyring"
+    case Pass:
+        return "pass password store"
+    case File:
+        return "encrypted file"
+    case Env:
+        return "plain text .env file"
+    }
+    return kind
+}
+
+// commandError returns the trimmed output of a failed command, or the
+// error itself when there was none.
+func commandError(output []byte, err error) string {
+    if msg := strings.TrimSpace(string(output)); msg != "" {
+        return msg
+    }
+    return err.Error()
+}
@@ -0,0 +1,65 @@
+package credentials
+
+import (
+    "testing"
+
+    "jira-tools/internal/config"
+)
+
+func TestProfileStore(t *testing.T) {
+    memory := NewMemoryStore()
+    store := profileStore{Store: memory, prefix: "client/"}
+
+    if err := store.Set("JIRA_API_TOKEN", "client-token"); err != nil {
+        t.Fatalf("Set: %v", err)
+    }
+    memory.Secrets["JIRA_API_TOKEN"] = "default-token"
+    if got := memory.Secrets["client/JIRA_API_TOKEN"]; got != "client-token" {
+        t.Errorf("stored under client/JIRA_API_TOKEN = %q, want the prefixed key", got)
+    }
+    if got, err := store.Get("JIRA_API_TOKEN"); err != nil || got != "client-token" {
+        t.Errorf("Get = %q, %v, want the profile's token", got, err)
+    }
+    if err := store.Delete("JIRA_API_TOKEN"); err != nil {
+        t.Fatalf("Delete: %v", err)
+    }
+    if _, err := store.Get("JIRA_API_TOKEN"); err != ErrNotFound {
+        t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+    }
+    if got := memory.Secrets["JIRA_API_TOKEN"]; got != "default-token" {
+        t.Errorf("the default profile's token = %q, want it untouched", got)
+    }
+}
+
+func TestNewProfilePrefix(t *testing.T) {
+    tests := []struct {
+        profile string
+        prefix  string
+    }{
+        {"", ""},
+        {config.DefaultProfile, ""},
+        {"client", "client/"},
+    }
+    for _, tt := range tests {
+        store, err := New(Pass, Options{Profile: tt.profile})
+        if err != nil {
+            t.Fatalf("New(%q): %v", tt.profile, err)
+        }
+        prefix := ""
+        if p, ok := store.(profileStore); ok {
+            prefix = p.prefix
+        }
+        if prefix != tt.prefix {
+            t.Errorf("profile %q: prefix = %q, want %q", tt.profile, prefix, tt.prefix)
+        }
+    }
+
+    // Every profile has its own .env file, so the env store is not prefixed
+    store, err := New(Env, Options{EnvPath: "/tmp/.env", Profile: "client"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, ok := store.(profileStore); ok {
+        t.Error("the env store of a profile is prefixed")
+    }
+}
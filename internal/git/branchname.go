@@ -0,0 +1,115 @@
+package git
+
+import (
+    "bytes"
+    "fmt"
+    "os/exec"
+    "strings"
+    "text/template"
+    "unicode/utf8"
+
+    "jira-tools/internal/config"
+)
+
+// DefaultBranchTemplate produces names like feature/PROJ-123-add-login.
+const DefaultBranchTemplate = "{{.Type}}/{{.Key}}-{{.Slug}}"
+
+// BranchIssue holds the issue fields available to branch name templates.
+type BranchIssue struct {
+    Key       string
+    Summary   string
+    IssueType string
+    Assignee  string
+    Component string
+}
+
+// BranchTemplateData is the data a branch template is executed with.
+type BranchTemplateData struct {
+    Type      string
+    Key       string
+    Slug      string
+    Summary   string
+    IssueType string
+    Assignee  string
+    Component string
+}
+
+// FormatBranchName renders the branch name for an issue using the
+// project's branch template for the branch type.
+func FormatBranchName(branchConfig *config.BranchConfig, issue BranchIssue, branchType BranchType) (string, error) {
+    text := DefaultBranchTemplate
+    if branchConfig.BranchTemplate != "" {
+        text = branchConfig.BranchTemplate
+    }
+    if override, ok := branchConfig.BranchTemplates[string(branchType)]; ok && override != "" {
+        text = override
+    }
+
+    slug := func(text string) string {
+        return Slugify(text, branchConfig.Slug)
+    }
+    funcs := template.FuncMap{
+        "lower":   strings.ToLower,
+        "upper":   strings.ToUpper,
+        "slug":    slug,
+        "replace": strings.ReplaceAll,
+    }
+
+    tmpl, err := template.New("branch").Funcs(funcs).Option("missingkey=error").Parse(text)
+    if err != nil {
+        return "", fmt.Errorf("invalid branch template %q: %v", text, err)
+    }
+
+    data := BranchTemplateData{
+        Type:      string(branchType),
+        Key:       issue.Key,
+        Slug:      slug(issue.Summary),
+        Summary:   issue.Summary,
+        IssueType: slug(issue.IssueType),
+        Assignee:  slug(issue.Assignee),
+        Component: slug(issue.Component),
+    }
+    // Summaries in scripts without a transliteration leave nothing behind;
+    // fall back to the key unless the template already contains it.
+    if data.Slug == "" && !strings.Contains(text, ".Key") {
+        data.Slug = strings.ToLower(issue.Key)
+    }
+
+    name, err := renderBranchName(tmpl, data, branchConfig.BranchMaxLength)
+    if err != nil {
+        return "", err
+    }
+
+    if err := exec.Command("git", "check-ref-format", "--branch", name).Run(); err != nil {
+        return "", fmt.Errorf("branch template produced an invalid branch name %q", name)
+    }
+    return name, nil
+}
+
+// renderBranchName executes the template, dropping trailing words of the
+// slug until the name fits in maxLength. Names that still do not fit are
+// cut hard.
+func renderBranchName(tmpl *template.Template, data BranchTemplateData, maxLength int) (string, error) {
+    words := strings.Split(data.Slug, "-")
+    for {
+        var buf bytes.Buffer
+        if err := tmpl.Execute(&buf, data); err != nil {
+            return "", fmt.Errorf("failed to render branch template: %v", err)
+        }
+        name := strings.TrimRight(strings.TrimSpace(buf.String()), "-_./")
+
+        if maxLength <= 0 || len(name) <= maxLength {
+            return name, nil
+        }
+        if data.Slug == "" {
+            cut := maxLength
+            for cut > 0 && !utf8.RuneStart(name[cut]) {
+                cut--
+            }
+            return strings.TrimRight(name[:cut], "-_./"), nil
+        }
+
+        words = words[:len(words)-1]
+        data.Slug = strings.Join(words, "-")
+    }
+}
@@ -0,0 +1,92 @@
+package git
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+)
+
+// conventionalSubject matches "type(scope)!: subject".
+var conventionalSubject = regexp.MustCompile(`^([a-z]+)(\(([^)]*)\))?(!)?: \S`)
+
+// CleanCommitMessage drops the comment lines git removes from commit
+// messages and trims surrounding whitespace.
+func CleanCommitMessage(message string) string {
+    var lines []string
+    for _, line := range strings.Split(message, "\n") {
+        if strings.HasPrefix(line, "#") {
+            continue
+        }
+        lines = append(lines, strings.TrimRight(line, " \t\r"))
+    }
+    return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// IsGeneratedCommitMessage reports whether a message was written by git
+// itself (merges, reverts, fixup and squash commits) and should not be
+// validated.
+func IsGeneratedCommitMessage(message string) bool {
+    for _, prefix := range []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "} {
+        if strings.HasPrefix(message, prefix) {
+            return true
+        }
+    }
+    return false
+}
+
+// ConventionalSubject is a parsed "type(scope)!: description" subject.
+type ConventionalSubject struct {
+    Type        string
+    Scope       string
+    Breaking    bool
+    Description string
+}
+
+// ParseConventionalSubject splits a conventional commit subject such as
+// "feat(PROJ-1): add login". ok is false for other subjects.
+func ParseConventionalSubject(subject string) (parsed ConventionalSubject, ok bool) {
+    match := conventionalSubject.FindStringSubmatchIndex(subject)
+    if match == nil {
+        return ConventionalSubject{}, false
+    }
+    parsed.Type = subject[match[2]:match[3]]
+    if match[6] >= 0 {
+        parsed.Scope = subject[match[6]:match[7]]
+    }
+    parsed.Breaking = match[8] >= 0
+    // The pattern ends with the first character of the description
+    parsed.Description = strings.TrimSpace(subject[match[1]-1:])
+    return parsed, true
+}
+
+// ConventionalType returns the type of a conventional commit subject such
+// as "feat(PROJ-1): add login", or an empty string.
+func ConventionalType(subject string) string {
+    parsed, _ := ParseConventionalSubject(subject)
+    return parsed.Type
+}
+
+// InsertIssueKey adds issueKey to a commit message that does not mention it
+// yet. Conventional subjects without a scope get the key as scope
+// ("feat: x" becomes "feat(KEY): x"); other subjects are prefixed with "KEY: ".
+func InsertIssueKey(message, issueKey string) string {
+    if strings.Contains(message, issueKey) {
+        return message
+    }
+
+    subject, rest := message, ""
+    if i := strings.Index(message, "\n"); i >= 0 {
+        subject, rest = message[:i], message[i:]
+    }
+
+    if match := conventionalSubject.FindStringSubmatchIndex(subject); match != nil && match[4] < 0 {
+        // No scope: insert it right after the type
+        typeEnd := match[3]
+        return subject[:typeEnd] + "(" + issueKey + ")" + subject[typeEnd:] + rest
+    }
+    if strings.HasPrefix(subject, "#") {
+        // Empty message with git's comment template below it
+        return fmt.Sprintf("%s: \n%s", issueKey, message)
+    }
+    return fmt.Sprintf("%s: %s%s", issueKey, subject, rest)
+}
@@ -0,0 +1,52 @@
+package git
+
+import "testing"
+
+func TestParseConventionalSubject(t *testing.T) {
+    tests := []struct {
+        subject string
+        want    ConventionalSubject
+        ok      bool
+    }{
+        {"feat(PROJ-1): add login", ConventionalSubject{Type: "feat", Scope: "PROJ-1", Description: "add login"}, true},
+        {"fix: handle nil", ConventionalSubject{Type: "fix", Description: "handle nil"}, true},
+        {"feat(api)!: drop v1", ConventionalSubject{Type: "feat", Scope: "api", Breaking: true, Description: "drop v1"}, true},
+        {"chore!: bump Go", ConventionalSubject{Type: "chore", Breaking: true, Description: "bump Go"}, true},
+        {"refactor(): tidy", ConventionalSubject{Type: "refactor", Description: "tidy"}, true},
+        {"Feat: add login", ConventionalSubject{}, false},
+        {"feat:add login", ConventionalSubject{}, false},
+        {"feat: ", ConventionalSubject{}, false},
+        {"PROJ-1: add login", ConventionalSubject{}, false},
+        {"Merge branch 'develop'", ConventionalSubject{}, false},
+    }
+    for _, tt := range tests {
+        got, ok := ParseConventionalSubject(tt.subject)
+        if ok != tt.ok || got != tt.want {
+            t.Errorf("ParseConventionalSubject(%q) = %+v, %v, want %+v, %v", tt.subject, got, ok, tt.want, tt.ok)
+        }
+    }
+}
+
+func TestInsertIssueKey(t *testing.T) {
+    tests := []struct {
+        name    string
+        message string
+        want    string
+    }{
+        {"conventional without scope", "feat: add login", "feat(PROJ-1): add login"},
+        {"breaking without scope", "feat!: drop v1", "feat(PROJ-1)!: drop v1"},
+        {"conventional with scope", "fix(api): handle nil", "PROJ-1: fix(api): handle nil"},
+        {"plain subject", "Add login", "PROJ-1: Add login"},
+        {"body kept", "feat: add login\n\nUses OAuth.", "feat(PROJ-1): add login\n\nUses OAuth."},
+        {"key already in the body", "Add login\n\nFor PROJ-1", "Add login\n\nFor PROJ-1"},
+        {"key already in the subject", "feat(PROJ-1): add login", "feat(PROJ-1): add login"},
+        {"empty message with comments", "# Please enter the commit message\n# Lines starting with '#' are ignored", "PROJ-1: \n# Please enter the commit message\n# Lines starting with '#' are ignored"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := InsertIssueKey(tt.message, "PROJ-1"); got != tt.want {
+                t.Errorf("InsertIssueKey(%q) = %q, want %q", tt.message, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+    "bytes"
+    "fmt"
+    "strings"
+    "text/template"
+
+    "jira-tools/internal/config"
+)
+
+// DefaultCommitTemplate produces "feat(PROJ-123): summary", using the
+// user-supplied message instead of the issue summary when there is one.
+const DefaultCommitTemplate = "{{.Type}}({{.Key}}): {{if .Message}}{{.Message}}{{else}}{{.Summary}}{{end}}"
+
+// CommitTemplateData is the data a commit template is executed with.
+type CommitTemplateData struct {
+    Type    string
+    Key     string
+    Summary string
+    // Message is the message passed with -m, if any.
+    Message     string
+    Branch      string
+    Files       []string
+    URL         string
+    IssueType   string
+    Status      string
+    Priority    string
+    Assignee    string
+    Description string
+    Labels      []string
+    Components  []string
+}
+
+var commitTemplateFuncs = template.FuncMap{
+    "lower": strings.ToLower,
+    "upper": strings.ToUpper,
+    "join":  strings.Join,
+    "trim":  strings.TrimSpace,
+}
+
+// FormatCommitMessage renders the project's commit template. Surrounding
+// whitespace is trimmed and runs of blank lines left by empty fields are
+// collapsed.
+func FormatCommitMessage(branchConfig *config.BranchConfig, data CommitTemplateData) (string, error) {
+    text := DefaultCommitTemplate
+    if branchConfig != nil && branchConfig.CommitTemplate != "" {
+        text = branchConfig.CommitTemplate
+    }
+
+    tmpl, err := template.New("commit").Funcs(commitTemplateFuncs).Option("missingkey=error").Parse(text)
+    if err != nil {
+        return "", fmt.Errorf("invalid commit template: %v", err)
+    }
+
+    var buf bytes.Buffer
+    if err := tmpl.Execute(&buf, data); err != nil {
+        return "", fmt.Errorf("failed to render commit template: %v", err)
+    }
+
+    message := strings.TrimSpace(buf.String())
+    for strings.Contains(message, "\n\n\n") {
+        message = strings.ReplaceAll(message, "\n\n\n", "\n\n")
+    }
+    if message == "" {
+        return "", fmt.Errorf("commit template produced an empty message")
+    }
+    return message + "\n", nil
+}
@@ -0,0 +1,244 @@
+package git
+
+import (
+    "fmt"
+    "jira-tools/internal/config"
+    "os/exec"
+    "strings"
+)
+
+// FinishOptions controls how FinishBranch merges a branch.
+type FinishOptions struct {
+    // Strategy is config.MergeNoFF, config.MergeFF or config.MergeSquash.
+    Strategy string
+    // Message overrides the merge commit message.
+    Message string
+    // Tag, when set, is created on the merge into the first target.
+    Tag string
+    // Keep keeps the branch after it has been merged.
+    Keep bool
+    // KeepRemote keeps the remote branch after it has been merged.
+    KeepRemote bool
+    // Push pushes the targets and the tag to origin.
+    Push bool
+}
+
+// FinishTargets returns the branches a finished branch of the given type is
+// merged into, in merge order. Hotfix and release branches go to
+// production first and then back to development.
+func FinishTargets(branchConfig *config.BranchConfig, branchType BranchType) ([]string, error) {
+    switch branchType {
+    case FeatureBranch, BugfixBranch:
+        return []string{branchConfig.DevelopmentBranch}, nil
+    case HotfixBranch, ReleaseBranch:
+        if !branchConfig.IsMonorepo || branchConfig.ProductionBranch == "" {
+            return []string{branchConfig.DevelopmentBranch}, nil
+        }
+        return []string{branchConfig.ProductionBranch, branchConfig.DevelopmentBranch}, nil
+    default:
+        return nil, fmt.Errorf("cannot finish branch type %q, expected feature, bugfix, hotfix or release", branchType)
+    }
+}
+
+// FinishBranch merges branch into each target. If any merge or the tag
+// fails, every target is reset to where it was, the original branch is
+// checked out again and the error is returned, leaving the repository
+// unchanged. A failed push keeps the local merges, tag and branch, and the
+// error tells how to complete the finish.
+func FinishBranch(branch string, targets []string, opts FinishOptions) error {
+    if dirty, err := hasUncommittedChanges(); err != nil {
+        return err
+    } else if dirty {
+        return fmt.Errorf("working tree has uncommitted changes, commit or stash them first")
+    }
+    if !BranchExists(branch) {
+        return fmt.Errorf("branch %s does not exist", branch)
+    }
+    if opts.Tag != "" && TagExists(opts.Tag) {
+        return fmt.Errorf("tag %s already exists", opts.Tag)
+    }
+
+    original, err := GetCurrentBranch()
+    if err != nil {
+        return err
+    }
+
+    // Remember where every target was so a failed merge can be rolled back
+    saved := make(map[string]string, len(targets))
+    for _, target := range targets {
+        if target == branch {
+            return fmt.Errorf("cannot merge %s into itself", branch)
+        }
+        sha, err := revParse("refs/heads/" + target)
+        if err != nil {
+            return fmt.Errorf("target branch %s does not exist", target)
+        }
+        saved[target] = sha
+    }
+
+    var tagTarget string
+    for i, target := range targets {
+        if err := mergeInto(branch, target, opts); err != nil {
+            rollbackFinish(original, targets[:i+1], saved)
+            return err
+        }
+        fmt.Printf("Merged %s into %s\n", branch, target)
+        if i == 0 {
+            tagTarget, _ = revParse("HEAD")
+        }
+    }
+
+    if opts.Tag != "" {
+        message := "Release " + opts.Tag
+        if output, err := exec.Command("git", "tag", "-a", opts.Tag, "-m", message, tagTarget).CombinedOutput(); err != nil {
+            rollbackFinish(original, targets, saved)
+            return fmt.Errorf("failed to create tag %s: %s\nThe merges were rolled back, %s is unchanged", opts.Tag, commandError(output, err), strings.Join(targets, " and "))
+        }
+        fmt.Printf("Tagged %s as %s\n", targets[0], opts.Tag)
+    }
+
+    if opts.Push {
+        // Atomic, so origin gets either all targets and the tag or nothing
+        args := append([]string{"push", "--atomic", "origin"}, targets...)
+        if opts.Tag != "" {
+            args = append(args, "refs/tags/"+opts.Tag)
+        }
+        if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+            done := "merged into " + strings.Join(targets, " and ")
+            if opts.Tag != "" {
+                done += " and tagged " + opts.Tag
+            }
+            next := []string{"git " + strings.Join(args, " ")}
+            if !opts.Keep {
+                next = append(next, "git branch "+deleteFlag(opts)+" "+branch)
+                if !opts.KeepRemote && RemoteBranchExists(branch) {
+                    next = append(next, "git push origin --delete "+branch)
+                }
+            }
+            return fmt.Errorf("failed to push %s: %s\n%s is %s locally, nothing was pushed and the branch was kept. To complete the finish, run:\n  %s",
+                strings.Join(targets, ", "), commandError(output, err), branch, done, strings.Join(next, "\n  "))
+        }
+        fmt.Printf("Pushed %s to origin\n", strings.Join(targets, ", "))
+    }
+
+    if opts.Keep {
+        return nil
+    }
+    return deleteFinishedBranch(branch, opts)
+}
+
+// mergeInto checks out target and merges branch into it.
+func mergeInto(branch, target string, opts FinishOptions) error {
+    if output, err := exec.Command("git", "checkout", target).CombinedOutput(); err != nil {
+        return fmt.Errorf("failed to checkout %s: %s", target, commandError(output, err))
+    }
+
+    message := opts.Message
+    if message == "" {
+        message = fmt.Sprintf("Merge branch '%s' into %s", branch, target)
+    }
+
+    switch opts.Strategy {
+    case config.MergeSquash:
+        if output, err := exec.Command("git", "merge", "--squash", branch).CombinedOutput(); err != nil {
+            return fmt.Errorf("failed to merge %s into %s: %s", branch, target, commandError(output, err))
+        }
+        staged, err := GetStagedFiles()
+        if err != nil {
+            return err
+        }
+        if len(staged) == 0 {
+            // Already merged, nothing to commit
+            return nil
+        }
+        if output, err := exec.Command("git", "commit", "-m", message).CombinedOutput(); err != nil {
+            return fmt.Errorf("failed to commit squashed %s into %s: %s", branch, target, commandError(output, err))
+        }
+    case config.MergeFF:
+        if output, err := exec.Command("git", "merge", "--ff", "--no-edit", "-m", message, branch).CombinedOutput(); err != nil {
+            return fmt.Errorf("failed to merge %s into %s: %s", branch, target, commandError(output, err))
+        }
+    case config.MergeNoFF, "":
+        if output, err := exec.Command("git", "merge", "--no-ff", "--no-edit", "-m", message, branch).CombinedOutput(); err != nil {
+            return fmt.Errorf("failed to merge %s into %s: %s", branch, target, commandError(output, err))
+        }
+    default:
+        return fmt.Errorf("invalid merge strategy %q, expected %s, %s or %s", opts.Strategy, config.MergeNoFF, config.MergeFF, config.MergeSquash)
+    }
+    return nil
+}
+
+// rollbackFinish undoes a partly finished merge: it aborts the merge in
+// progress, resets the touched targets and checks out the original branch.
+func rollbackFinish(original string, targets []string, saved map[string]string) {
+    exec.Command("git", "merge", "--abort").Run()
+    exec.Command("git", "reset", "--hard", "--quiet").Run()
+
+    for _, target := range targets {
+        if err := exec.Command("git", "checkout", "--quiet", target).Run(); err != nil {
+            continue
+        }
+        exec.Command("git", "reset", "--hard", "--quiet", saved[target]).Run()
+    }
+    exec.Command("git", "checkout", "--quiet", original).Run()
+}
+
+// deleteFinishedBranch removes the local branch and, unless told to keep
+// it, its counterpart on origin.
+func deleteFinishedBranch(branch string, opts FinishOptions) error {
+    if output, err := exec.Command("git", "branch", deleteFlag(opts), branch).CombinedOutput(); err != nil {
+        return fmt.Errorf("failed to delete branch %s: %s", branch, commandError(output, err))
+    }
+    fmt.Printf("Deleted branch %s\n", branch)
+
+    if opts.KeepRemote || !RemoteBranchExists(branch) {
+        return nil
+    }
+    if output, err := exec.Command("git", "push", "origin", "--delete", branch).CombinedOutput(); err != nil {
+        return fmt.Errorf("failed to delete remote branch %s: %s", branch, commandError(output, err))
+    }
+    fmt.Printf("Deleted remote branch origin/%s\n", branch)
+    return nil
+}
+
+// deleteFlag is the git branch flag that deletes a finished branch.
+// Squashed branches are not ancestors of the target, so git branch -d would
+// refuse them.
+func deleteFlag(opts FinishOptions) string {
+    if opts.Strategy == config.MergeSquash {
+        return "-D"
+    }
+    return "-d"
+}
+
+// hasUncommittedChanges reports whether tracked files have staged or
+// unstaged changes.
+func hasUncommittedChanges() (bool, error) {
+    output, err := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output()
+    if err != nil {
+        return false, fmt.Errorf("failed to get status: %v", err)
+    }
+    return strings.TrimSpace(string(output)) != "", nil
+}
+
+// BranchExists reports whether a local branch exists.
+func BranchExists(name string) bool {
+    return verifyRef("refs/heads/"+name) == nil
+}
+
+// TagExists reports whether a tag exists.
+func TagExists(name string) bool {
+    return verifyRef("refs/tags/"+name) == nil
+}
+
+func verifyRef(ref string) error {
+    return exec.Command("git", "show-ref", "--verify", "--quiet", ref).Run()
+}
+
+func revParse(rev string) (string, error) {
+    output, err := exec.Command("git", "rev-parse", "--verify", "--quiet", rev).Output()
+    if err != nil {
+        return "", fmt.Errorf("failed to resolve %s: %v", rev, err)
+    }
+    return strings.TrimSpace(string(output)), nil
+}
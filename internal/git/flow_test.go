@@ -0,0 +1,110 @@
+package git
+
+import (
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// newHotfixRepo creates a repository with main and develop branches and a
+// hotfix branch with one commit, checked out.
+func newHotfixRepo(t *testing.T) string {
+    t.Helper()
+    root := chdirToNewRepo(t)
+    writeFile(t, "app.txt", "v1\n")
+    runGit(t, "add", "app.txt")
+    runGit(t, "commit", "-q", "-m", "initial")
+    runGit(t, "branch", "-M", "main")
+    runGit(t, "branch", "develop")
+    runGit(t, "checkout", "-q", "-b", "hotfix/1.0.1")
+    writeFile(t, "app.txt", "v1 fixed\n")
+    runGit(t, "commit", "-q", "-a", "-m", "fix")
+    return root
+}
+
+func TestFinishBranchTagFailureRollsBack(t *testing.T) {
+    newHotfixRepo(t)
+    before := map[string]string{
+        "main":    runGit(t, "rev-parse", "main"),
+        "develop": runGit(t, "rev-parse", "develop"),
+    }
+
+    err := FinishBranch("hotfix/1.0.1", []string{"main", "develop"}, FinishOptions{Tag: "v1..1"})
+    if err == nil {
+        t.Fatal("FinishBranch succeeded with an invalid tag name")
+    }
+    if !strings.Contains(err.Error(), "rolled back") {
+        t.Errorf("error = %q, want it to say the merges were rolled back", err)
+    }
+    for branch, sha := range before {
+        if got := runGit(t, "rev-parse", branch); got != sha {
+            t.Errorf("%s moved from %s to %s", branch, sha, got)
+        }
+    }
+    if current := runGit(t, "rev-parse", "--abbrev-ref", "HEAD"); current != "hotfix/1.0.1" {
+        t.Errorf("checked out %s, want hotfix/1.0.1", current)
+    }
+}
+
+func TestFinishBranchPush(t *testing.T) {
+    tests := []struct {
+        name   string
+        remote bool
+    }{
+        {name: "pushed", remote: true},
+        {name: "push failed", remote: false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            root := newHotfixRepo(t)
+            remote := filepath.Join(root, "missing.git")
+            if tt.remote {
+                remote = t.TempDir()
+                runGit(t, "init", "-q", "--bare", remote)
+            }
+            runGit(t, "remote", "add", "origin", remote)
+
+            err := FinishBranch("hotfix/1.0.1", []string{"main", "develop"}, FinishOptions{Tag: "v1.0.1", Push: true})
+            if !TagExists("v1.0.1") {
+                t.Error("tag v1.0.1 was not created")
+            }
+            for _, target := range []string{"main", "develop"} {
+                if content := runGit(t, "show", target+":app.txt"); content != "v1 fixed" {
+                    t.Errorf("%s has %q, want the fix merged", target, content)
+                }
+            }
+
+            if tt.remote {
+                if err != nil {
+                    t.Fatalf("FinishBranch: %v", err)
+                }
+                if BranchExists("hotfix/1.0.1") {
+                    t.Error("hotfix/1.0.1 was not deleted")
+                }
+                refs := runGit(t, "--git-dir", remote, "show-ref")
+                for _, ref := range []string{"refs/heads/main", "refs/heads/develop", "refs/tags/v1.0.1"} {
+                    if !strings.Contains(refs, ref) {
+                        t.Errorf("origin is missing %s:\n%s", ref, refs)
+                    }
+                }
+                return
+            }
+
+            if err == nil {
+                t.Fatal("FinishBranch succeeded without a remote")
+            }
+            for _, want := range []string{
+                "merged into main and develop and tagged v1.0.1 locally",
+                "git push --atomic origin main develop refs/tags/v1.0.1",
+                "git branch -d hotfix/1.0.1",
+            } {
+                if !strings.Contains(err.Error(), want) {
+                    t.Errorf("error = %q, want it to contain %q", err, want)
+                }
+            }
+            if !BranchExists("hotfix/1.0.1") {
+                t.Error("hotfix/1.0.1 was deleted after a failed push")
+            }
+        })
+    }
+}
@@ -1,10 +1,12 @@
 package git
 
 import (
-	"fmt"
+    "fmt"
+    "jira-tools/internal/config"
+    "os"
     "os/exec"
+    "regexp"
     "strings"
-    "jira-tools/internal/config"
 )
 
 type BranchType string
@@ -16,6 +18,9 @@ const (
     ReleaseBranch BranchType = "release"
 )
 
+// DefaultIssueKeyPattern matches Jira issue keys such as PROJ-123.
+const DefaultIssueKeyPattern = `[A-Z][A-Z0-9_]+-[0-9]+`
+
 func GetProjectRoot() (string, error) {
     cmd := exec.Command("git", "rev-parse", "--show-toplevel")
     output, err := cmd.Output()
@@ -36,7 +41,7 @@ func GetAvailableBranches() ([]string, error) {
     return branches, nil
 }
 
-func CreateBranch(issueKey, summary string, branchType BranchType) error {
+func CreateBranch(issue BranchIssue, branchType BranchType) error {
     projectRoot, err := GetProjectRoot()
     if err != nil {
         return err
@@ -48,23 +53,38 @@ func CreateBranch(issueKey, summary string, branchType BranchType) error {
     }
 
     // Determine base branch
-    var baseBranch string
-    if branchConfig.IsMonorepo {
-        switch branchType {
-        case FeatureBranch, BugfixBranch:
-            baseBranch = branchConfig.DevelopmentBranch
-        case HotfixBranch, ReleaseBranch:
-            baseBranch = branchConfig.ProductionBranch
-        default:
-            return fmt.Errorf("invalid branch type: %s", branchType)
-        }
-    } else {
-        baseBranch = branchConfig.DevelopmentBranch
+    baseBranch, err := BaseBranch(branchConfig, branchType)
+    if err != nil {
+        return err
     }
 
     // Create branch name
-    branchName := fmt.Sprintf("%s/%s-%s", branchType, issueKey, formatBranchName(summary))
+    branchName, err := FormatBranchName(branchConfig, issue, branchType)
+    if err != nil {
+        return err
+    }
 
+    return CreateBranchFrom(branchName, baseBranch)
+}
+
+// BaseBranch returns the branch a new branch of the given type starts from.
+func BaseBranch(branchConfig *config.BranchConfig, branchType BranchType) (string, error) {
+    if !branchConfig.IsMonorepo {
+        return branchConfig.DevelopmentBranch, nil
+    }
+
+    switch branchType {
+    case FeatureBranch, BugfixBranch:
+        return branchConfig.DevelopmentBranch, nil
+    case HotfixBranch, ReleaseBranch:
+        return branchConfig.ProductionBranch, nil
+    default:
+        return "", fmt.Errorf("invalid branch type: %s", branchType)
+    }
+}
+
+// CreateBranchFrom checks out baseBranch and creates branchName from it.
+func CreateBranchFrom(branchName, baseBranch string) error {
     // Checkout base branch
     if err := exec.Command("git", "checkout", baseBranch).Run(); err != nil {
         return fmt.Errorf("failed to checkout %s branch: %v", baseBranch, err)
@@ -79,32 +99,152 @@ func CreateBranch(issueKey, summary string, branchType BranchType) error {
     return nil
 }
 
-func CommitChanges(issueKey, commitType, summary string) error {
-    // Stage all changes
-    if err := exec.Command("git", "add", ".").Run(); err != nil {
-        return fmt.Errorf("failed to stage changes: %v", err)
+// StageAll stages every change in the working tree, including deletions
+// and untracked files.
+func StageAll() error {
+    if output, err := exec.Command("git", "add", "-A").CombinedOutput(); err != nil {
+        return fmt.Errorf("failed to stage changes: %s", commandError(output, err))
+    }
+    return nil
+}
+
+// GetStagedFiles lists the paths staged for the next commit.
+func GetStagedFiles() ([]string, error) {
+    output, err := exec.Command("git", "diff", "--cached", "--name-only").Output()
+    if err != nil {
+        return nil, fmt.Errorf("failed to list staged files: %v", err)
     }
+    var files []string
+    for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+        if line != "" {
+            files = append(files, line)
+        }
+    }
+    return files, nil
+}
 
-    // Create commit message
-    message := fmt.Sprintf("%s(%s): %s", commitType, issueKey, summary)
+// CommitChanges commits the staged changes with message. With edit, git
+// opens the configured editor pre-filled with the message first.
+func CommitChanges(message string, edit bool) error {
+    msgFile, err := os.CreateTemp("", "jt-commit-*.txt")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(msgFile.Name())
+
+    if _, err := msgFile.WriteString(message); err != nil {
+        msgFile.Close()
+        return err
+    }
+    if err := msgFile.Close(); err != nil {
+        return err
+    }
+
+    args := []string{"commit", "-F", msgFile.Name()}
+    if edit {
+        args = append(args, "--edit")
+    }
+    cmd := exec.Command("git", args...)
 
     // Commit changes
-    if err := exec.Command("git", "commit", "-m", message).Run(); err != nil {
-        return fmt.Errorf("failed to commit changes: %v", err)
+    if edit {
+        cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+        if err := cmd.Run(); err != nil {
+            return fmt.Errorf("failed to commit changes: %v", err)
+        }
+    } else if output, err := cmd.CombinedOutput(); err != nil {
+        return fmt.Errorf("failed to commit changes: %s", commandError(output, err))
     }
 
-    fmt.Printf("Changes committed with message: %s\n", message)
+    if edit {
+        // The message may have changed in the editor
+        if output, err := exec.Command("git", "log", "-1", "--format=%B").Output(); err == nil {
+            message = string(output)
+        }
+    }
+    fmt.Printf("Changes committed with message: %s\n", strings.TrimSpace(message))
     return nil
 }
 
-func PushBranch() error {
-    // Get current branch
+func GetCurrentBranch() (string, error) {
     cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
     branchBytes, err := cmd.Output()
     if err != nil {
-        return fmt.Errorf("failed to get current branch: %v", err)
+        return "", fmt.Errorf("failed to get current branch: %v", err)
+    }
+    return strings.TrimSpace(string(branchBytes)), nil
+}
+
+// BranchTypeOf returns the type prefix of a branch such as feature/PROJ-1-x.
+func BranchTypeOf(branch string) BranchType {
+    if i := strings.Index(branch, "/"); i > 0 {
+        return BranchType(branch[:i])
+    }
+    return ""
+}
+
+// GetIssueKeyPattern returns the issue key pattern configured for the
+// current project, or DefaultIssueKeyPattern.
+func GetIssueKeyPattern() (*regexp.Regexp, error) {
+    pattern := DefaultIssueKeyPattern
+    if projectRoot, err := GetProjectRoot(); err == nil {
+        if branchConfig, err := config.LoadProjectBranchConfig(projectRoot); err == nil && branchConfig.IssueKeyPattern != "" {
+            pattern = branchConfig.IssueKeyPattern
+        }
+    }
+
+    re, err := regexp.Compile(pattern)
+    if err != nil {
+        return nil, fmt.Errorf("invalid issue_key_pattern %q: %v", pattern, err)
+    }
+    return re, nil
+}
+
+// IsIssueKey reports whether value is exactly an issue key.
+func IsIssueKey(value string) bool {
+    re, err := GetIssueKeyPattern()
+    if err != nil {
+        return false
+    }
+    matched, err := regexp.MatchString("^(?:"+re.String()+")$", value)
+    return err == nil && matched
+}
+
+// IssueKeyFromBranch returns the first Jira issue key found in a branch
+// name, or an empty string.
+func IssueKeyFromBranch(branch string) string {
+    re, err := GetIssueKeyPattern()
+    if err != nil {
+        return ""
+    }
+    return re.FindString(branch)
+}
+
+// GetCurrentIssueKey returns the issue key encoded in the checked out
+// branch, e.g. PROJ-123 for feature/PROJ-123-add-login.
+func GetCurrentIssueKey() (string, error) {
+    branch, err := GetCurrentBranch()
+    if err != nil {
+        return "", err
+    }
+
+    re, err := GetIssueKeyPattern()
+    if err != nil {
+        return "", err
+    }
+    issueKey := re.FindString(branch)
+    if issueKey == "" {
+        return "", fmt.Errorf("branch %q does not contain an issue key, pass the card number explicitly", branch)
+    }
+    return issueKey, nil
+}
+
+func PushBranch() error {
+    // Get current branch
+    branch, err := GetCurrentBranch()
+    if err != nil {
+        return err
     }
-    branch := strings.TrimSpace(string(branchBytes))
 
     // Push to remote
     if err := exec.Command("git", "push", "-u", "origin", branch).Run(); err != nil {
@@ -114,21 +254,3 @@ func PushBranch() error {
     fmt.Printf("Successfully pushed branch %s to remote\n", branch)
     return nil
 }
-
-func formatBranchName(name string) string {
-    // Convert to lowercase
-    name = strings.ToLower(name)
-    // Replace spaces and special characters with hyphens
-    name = strings.Map(func(r rune) rune {
-        if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
-            return r
-        }
-        return '-'
-    }, name)
-    // Remove consecutive hyphens
-    for strings.Contains(name, "--") {
-        name = strings.ReplaceAll(name, "--", "-")
-    }
-    // Trim hyphens from ends
-    return strings.Trim(name, "-")
-}
@@ -0,0 +1,160 @@
+package git
+
+import (
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+)
+
+// hookMarker identifies hook scripts written by jt.
+const hookMarker = "# installed by jt (jira-tools)"
+
+// chainedSuffix is appended to a pre-existing hook that jt moved aside; the
+// jt hook runs it first so existing hooks keep working.
+const chainedSuffix = ".local"
+
+// GetHooksDir returns the directory git runs hooks from, honouring
+// core.hooksPath.
+func GetHooksDir() (string, error) {
+    projectRoot, err := GetProjectRoot()
+    if err != nil {
+        return "", err
+    }
+
+    output, err := exec.Command("git", "config", "--get", "core.hooksPath").Output()
+    if err == nil {
+        hooksPath := strings.TrimSpace(string(output))
+        if strings.HasPrefix(hooksPath, "~/") {
+            if home, err := os.UserHomeDir(); err == nil {
+                hooksPath = filepath.Join(home, hooksPath[2:])
+            }
+        }
+        if !filepath.IsAbs(hooksPath) {
+            hooksPath = filepath.Join(projectRoot, hooksPath)
+        }
+        return hooksPath, nil
+    }
+
+    output, err = exec.Command("git", "rev-parse", "--git-common-dir").Output()
+    if err != nil {
+        return "", fmt.Errorf("failed to locate git directory: %v", err)
+    }
+    gitDir := strings.TrimSpace(string(output))
+    if !filepath.IsAbs(gitDir) {
+        wd, err := os.Getwd()
+        if err != nil {
+            return "", err
+        }
+        gitDir = filepath.Join(wd, gitDir)
+    }
+    return filepath.Join(gitDir, "hooks"), nil
+}
+
+// InstallHook writes a jt hook script running body. An existing hook that
+// was not written by jt is kept as <name>.local and chained before body.
+func InstallHook(name, body string) error {
+    hooksDir, err := GetHooksDir()
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(hooksDir, 0755); err != nil {
+        return err
+    }
+
+    path := filepath.Join(hooksDir, name)
+    if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), hookMarker) {
+        chained := path + chainedSuffix
+        if _, err := os.Stat(chained); err == nil {
+            return fmt.Errorf("cannot chain existing %s hook: %s already exists", name, chained)
+        }
+        if err := os.Rename(path, chained); err != nil {
+            return fmt.Errorf("failed to move existing %s hook: %v", name, err)
+        }
+        fmt.Printf("Existing %s hook moved to %s and will still run\n", name, chained)
+    }
+
+    script := fmt.Sprintf(`#!/bin/sh
+%s
+if [ -x "$0%s" ]; then
+  "$0%s" "$@" || exit $?
+fi
+%s
+`, hookMarker, chainedSuffix, chainedSuffix, strings.TrimSpace(body))
+
+    return os.WriteFile(path, []byte(script), 0755)
+}
+
+// UninstallHook removes a jt hook and restores the hook it chained, if any.
+// Hooks not written by jt are left alone.
+func UninstallHook(name string) error {
+    hooksDir, err := GetHooksDir()
+    if err != nil {
+        return err
+    }
+
+    path := filepath.Join(hooksDir, name)
+    installed, err := hookInstalledAt(path)
+    if err != nil || !installed {
+        return err
+    }
+
+    if err := os.Remove(path); err != nil {
+        return err
+    }
+    chained := path + chainedSuffix
+    if _, err := os.Stat(chained); err == nil {
+        return os.Rename(chained, path)
+    }
+    return nil
+}
+
+// HookInstalled reports whether the named hook is a jt hook.
+func HookInstalled(name string) (bool, error) {
+    hooksDir, err := GetHooksDir()
+    if err != nil {
+        return false, err
+    }
+    return hookInstalledAt(filepath.Join(hooksDir, name))
+}
+
+func hookInstalledAt(path string) (bool, error) {
+    content, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return false, nil
+    }
+    if err != nil {
+        return false, err
+    }
+    return strings.Contains(string(content), hookMarker), nil
+}
+
+// HookBody returns hook script lines running this jt binary with args. The
+// binary is called by the path it was started as, so hooks keep using it
+// after an upgrade behind a symlink. When that path is gone, e.g. after
+// `go run` or a reinstall to another prefix, the hook falls back to jt on
+// PATH, and skips with a warning when there is none rather than failing
+// every commit.
+func HookBody(args string) string {
+    exe, err := os.Executable()
+    if err != nil {
+        exe = "jt"
+    }
+    return hookBody(exe, args)
+}
+
+func hookBody(exe, args string) string {
+    return fmt.Sprintf(`jt=%s
+if [ ! -x "$jt" ]; then
+  jt=$(command -v jt) || {
+    echo "jt: not found, skipping the $(basename "$0") hook" >&2
+    exit 0
+  }
+fi
+"$jt" %s`, shellQuote(filepath.ToSlash(exe)), args)
+}
+
+func shellQuote(s string) string {
+    return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
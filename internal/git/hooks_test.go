@@ -0,0 +1,147 @@
+package git
+
+import (
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestInstallHookChainsExistingHook(t *testing.T) {
+    repo := chdirToNewRepo(t)
+    hooks := filepath.Join(repo, ".git", "hooks")
+    log := filepath.Join(repo, "hooks.log")
+    existing := "#!/bin/sh\necho local >> '" + log + "'\n"
+    writeFile(t, filepath.Join(hooks, "commit-msg"), existing)
+    if err := os.Chmod(filepath.Join(hooks, "commit-msg"), 0755); err != nil {
+        t.Fatal(err)
+    }
+
+    body := "echo jt >> '" + log + "'"
+    if err := InstallHook("commit-msg", body); err != nil {
+        t.Fatalf("InstallHook: %v", err)
+    }
+    // Reinstalling replaces the jt hook and keeps the chained one
+    if err := InstallHook("commit-msg", body); err != nil {
+        t.Fatalf("InstallHook again: %v", err)
+    }
+    if installed, err := HookInstalled("commit-msg"); err != nil || !installed {
+        t.Errorf("HookInstalled = %v, %v, want true", installed, err)
+    }
+    if data, err := os.ReadFile(filepath.Join(hooks, "commit-msg.local")); err != nil || string(data) != existing {
+        t.Errorf("commit-msg.local = %q, %v, want the existing hook", data, err)
+    }
+
+    runGit(t, "commit", "-q", "--allow-empty", "-m", "chained")
+    data, err := os.ReadFile(log)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if string(data) != "local\njt\n" {
+        t.Errorf("hooks ran %q, want the existing hook before jt's", data)
+    }
+
+    if err := UninstallHook("commit-msg"); err != nil {
+        t.Fatalf("UninstallHook: %v", err)
+    }
+    if data, err := os.ReadFile(filepath.Join(hooks, "commit-msg")); err != nil || string(data) != existing {
+        t.Errorf("commit-msg after uninstall = %q, %v, want the existing hook restored", data, err)
+    }
+    if _, err := os.Stat(filepath.Join(hooks, "commit-msg.local")); !os.IsNotExist(err) {
+        t.Errorf("commit-msg.local still exists after uninstall: %v", err)
+    }
+    if installed, err := HookInstalled("commit-msg"); err != nil || installed {
+        t.Errorf("HookInstalled after uninstall = %v, %v, want false", installed, err)
+    }
+}
+
+func TestInstallHookChainConflict(t *testing.T) {
+    repo := chdirToNewRepo(t)
+    hooks := filepath.Join(repo, ".git", "hooks")
+    writeFile(t, filepath.Join(hooks, "post-commit"), "#!/bin/sh\n")
+    writeFile(t, filepath.Join(hooks, "post-commit.local"), "#!/bin/sh\n")
+
+    err := InstallHook("post-commit", "true")
+    if err == nil || !strings.Contains(err.Error(), "already exists") {
+        t.Errorf("InstallHook error = %v, want the chained hook conflict", err)
+    }
+}
+
+func TestUninstallHookKeepsForeignHooks(t *testing.T) {
+    repo := chdirToNewRepo(t)
+    path := filepath.Join(repo, ".git", "hooks", "post-checkout")
+    writeFile(t, path, "#!/bin/sh\necho mine\n")
+
+    if err := UninstallHook("post-checkout"); err != nil {
+        t.Fatalf("UninstallHook: %v", err)
+    }
+    if err := UninstallHook("pre-push"); err != nil {
+        t.Fatalf("UninstallHook of a missing hook: %v", err)
+    }
+    if _, err := os.Stat(path); err != nil {
+        t.Errorf("the hook not written by jt was removed: %v", err)
+    }
+}
+
+func TestHookBodyFallback(t *testing.T) {
+    dir := t.TempDir()
+    bin := filepath.Join(dir, "bin")
+    installed := filepath.Join(dir, "it's installed", "jt")
+    for _, path := range []string{filepath.Join(bin, "jt"), installed} {
+        if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+            t.Fatal(err)
+        }
+        writeFile(t, path, "#!/bin/sh\necho \""+path+" $*\"\n")
+        if err := os.Chmod(path, 0755); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    tests := []struct {
+        name   string
+        exe    string
+        path   string
+        stdout string
+        stderr string
+    }{
+        {
+            name:   "installed path",
+            exe:    installed,
+            path:   bin,
+            stdout: installed + " hooks run commit-msg msg",
+        },
+        {
+            name:   "stale path falls back to PATH",
+            exe:    filepath.Join(dir, "Cellar", "jt", "1.0", "bin", "jt"),
+            path:   bin,
+            stdout: filepath.Join(bin, "jt") + " hooks run commit-msg msg",
+        },
+        {
+            name:   "not found",
+            exe:    filepath.Join(dir, "missing", "jt"),
+            path:   filepath.Join(dir, "empty"),
+            stderr: "jt: not found, skipping the commit-msg hook",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            script := filepath.Join(t.TempDir(), "commit-msg")
+            writeFile(t, script, "#!/bin/sh\n"+hookBody(tt.exe, `hooks run commit-msg "$@"`)+"\n")
+
+            var stdout, stderr strings.Builder
+            cmd := exec.Command("/bin/sh", script, "msg")
+            cmd.Env = []string{"PATH=" + tt.path + ":/usr/bin:/bin"}
+            cmd.Stdout, cmd.Stderr = &stdout, &stderr
+            if err := cmd.Run(); err != nil {
+                t.Fatalf("hook failed: %v\n%s", err, stderr.String())
+            }
+            if got := strings.TrimSpace(stdout.String()); got != tt.stdout {
+                t.Errorf("stdout = %q, want %q", got, tt.stdout)
+            }
+            if got := strings.TrimSpace(stderr.String()); got != tt.stderr {
+                t.Errorf("stderr = %q, want %q", got, tt.stderr)
+            }
+        })
+    }
+}
@@ -0,0 +1,90 @@
+package git
+
+import (
+    "fmt"
+    "os/exec"
+    "strings"
+    "time"
+)
+
+// Commit is a commit as listed by Log.
+type Commit struct {
+    Hash    string
+    Author  string
+    Date    time.Time
+    Subject string
+    Body    string
+}
+
+// Message returns the full commit message.
+func (c Commit) Message() string {
+    if c.Body == "" {
+        return c.Subject
+    }
+    return c.Subject + "\n\n" + c.Body
+}
+
+const (
+    fieldSeparator  = "\x1f"
+    commitSeparator = "\x1e"
+)
+
+// Log lists the commits reachable from to but not from from, newest first.
+// An empty from lists the whole history of to; an empty to means HEAD.
+func Log(from, to string) ([]Commit, error) {
+    if to == "" {
+        to = "HEAD"
+    }
+    revRange := to
+    if from != "" {
+        revRange = from + ".." + to
+    }
+
+    format := strings.Join([]string{"%H", "%an", "%aI", "%s", "%b"}, fieldSeparator) + commitSeparator
+    output, err := exec.Command("git", "log", "--format="+format, revRange, "--").CombinedOutput()
+    if err != nil {
+        return nil, fmt.Errorf("failed to read commits %s: %s", revRange, commandError(output, err))
+    }
+
+    var commits []Commit
+    for _, record := range strings.Split(string(output), commitSeparator) {
+        record = strings.TrimLeft(record, "\n")
+        if record == "" {
+            continue
+        }
+        fields := strings.SplitN(record, fieldSeparator, 5)
+        if len(fields) < 5 {
+            continue
+        }
+        date, _ := time.Parse(time.RFC3339, fields[2])
+        commits = append(commits, Commit{
+            Hash:    fields[0],
+            Author:  fields[1],
+            Date:    date,
+            Subject: fields[3],
+            Body:    strings.TrimSpace(fields[4]),
+        })
+    }
+    return commits, nil
+}
+
+// IssueKeys returns the issue keys mentioned in the commit messages, in
+// order of first appearance.
+func IssueKeys(commits []Commit) ([]string, error) {
+    re, err := GetIssueKeyPattern()
+    if err != nil {
+        return nil, err
+    }
+
+    var keys []string
+    seen := make(map[string]bool)
+    for _, commit := range commits {
+        for _, key := range re.FindAllString(commit.Message(), -1) {
+            if !seen[key] {
+                seen[key] = true
+                keys = append(keys, key)
+            }
+        }
+    }
+    return keys, nil
+}
@@ -0,0 +1,81 @@
+package git
+
+import (
+    "fmt"
+    "net/url"
+    "os/exec"
+    "strings"
+)
+
+// Remote identifies a repository on a hosting service, e.g. host github.com
+// and path acme/api for git@github.com:acme/api.git.
+type Remote struct {
+    // Host is the host of the web interface, with the port of HTTPS
+    // remotes, e.g. gitlab.example.com:8443.
+    Host string
+    Path string
+}
+
+// GetRemoteURL returns the fetch URL of a remote such as origin.
+func GetRemoteURL(name string) (string, error) {
+    output, err := exec.Command("git", "remote", "get-url", name).CombinedOutput()
+    if err != nil {
+        return "", fmt.Errorf("failed to get URL of remote %s: %s", name, commandError(output, err))
+    }
+    return strings.TrimSpace(string(output)), nil
+}
+
+// GetRemote parses the URL of a remote.
+func GetRemote(name string) (*Remote, error) {
+    rawURL, err := GetRemoteURL(name)
+    if err != nil {
+        return nil, err
+    }
+    return ParseRemoteURL(rawURL)
+}
+
+// ParseRemoteURL understands HTTPS and SSH remotes, both as URLs
+// (ssh://git@host:22/owner/repo.git) and in scp form (git@host:owner/repo.git).
+func ParseRemoteURL(rawURL string) (*Remote, error) {
+    rawURL = strings.TrimSpace(rawURL)
+
+    var host, path string
+    if strings.Contains(rawURL, "://") {
+        u, err := url.Parse(rawURL)
+        if err != nil {
+            return nil, fmt.Errorf("invalid remote URL %q: %v", rawURL, err)
+        }
+        // The port of an SSH remote is not the port of the web interface,
+        // the one of an HTTPS remote is
+        host, path = u.Host, u.Path
+        if u.Scheme != "http" && u.Scheme != "https" {
+            host = u.Hostname()
+        }
+    } else if i := strings.Index(rawURL, ":"); i > 0 {
+        host, path = rawURL[:i], rawURL[i+1:]
+        if at := strings.LastIndex(host, "@"); at >= 0 {
+            host = host[at+1:]
+        }
+    }
+
+    path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+    if host == "" || path == "" {
+        return nil, fmt.Errorf("unsupported remote URL %q", rawURL)
+    }
+    return &Remote{Host: host, Path: path}, nil
+}
+
+// RemoteBranchExists reports whether origin has a branch, as far as the
+// last fetch or push knows.
+func RemoteBranchExists(branch string) bool {
+    return verifyRef("refs/remotes/origin/"+branch) == nil
+}
+
+// BaseRef returns the revision to compare a branch against for base: the
+// local branch, or origin/<base> in clones that never checked it out.
+func BaseRef(base string) string {
+    if !BranchExists(base) && RemoteBranchExists(base) {
+        return "origin/" + base
+    }
+    return base
+}
@@ -0,0 +1,67 @@
+package git
+
+import "testing"
+
+func TestBaseRef(t *testing.T) {
+    origin := newHotfixRepo(t)
+    clone := t.TempDir()
+    runGit(t, "clone", "-q", "--branch", "main", origin, clone)
+    runGit(t, "-C", clone, "branch", "local-only")
+
+    chdir(t, clone)
+    tests := []struct {
+        base string
+        want string
+    }{
+        {"main", "main"},
+        {"develop", "origin/develop"},
+        {"local-only", "local-only"},
+        {"missing", "missing"},
+    }
+    for _, tt := range tests {
+        if got := BaseRef(tt.base); got != tt.want {
+            t.Errorf("BaseRef(%q) = %q, want %q", tt.base, got, tt.want)
+        }
+    }
+    commits, err := Log(BaseRef("develop"), "origin/hotfix/1.0.1")
+    if err != nil {
+        t.Fatalf("Log: %v", err)
+    }
+    if len(commits) != 1 || commits[0].Subject != "fix" {
+        t.Errorf("commits since develop = %+v, want the fix only", commits)
+    }
+}
+
+func TestParseRemoteURL(t *testing.T) {
+    tests := []struct {
+        url     string
+        want    Remote
+        wantErr bool
+    }{
+        {url: "git@github.com:acme/api.git", want: Remote{"github.com", "acme/api"}},
+        {url: "git@github.com:acme/api", want: Remote{"github.com", "acme/api"}},
+        {url: "ssh://git@gitlab.example.com:22/group/sub/repo.git", want: Remote{"gitlab.example.com", "group/sub/repo"}},
+        {url: "ssh://git@bitbucket.org/team/repo.git", want: Remote{"bitbucket.org", "team/repo"}},
+        {url: "https://github.com/acme/api.git", want: Remote{"github.com", "acme/api"}},
+        {url: "https://github.com/acme/api", want: Remote{"github.com", "acme/api"}},
+        {url: "https://user@bitbucket.org/team/repo.git", want: Remote{"bitbucket.org", "team/repo"}},
+        {url: "https://gitlab.example.com:8443/group/repo.git", want: Remote{"gitlab.example.com:8443", "group/repo"}},
+        {url: "https://github.com/acme/api/", want: Remote{"github.com", "acme/api"}},
+        {url: " git@github.com:acme/api.git\n", want: Remote{"github.com", "acme/api"}},
+        {url: "/srv/git/api.git", wantErr: true},
+        {url: "https://github.com/", wantErr: true},
+        {url: "", wantErr: true},
+    }
+    for _, tt := range tests {
+        got, err := ParseRemoteURL(tt.url)
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("ParseRemoteURL(%q) = %+v, want an error", tt.url, got)
+            }
+            continue
+        }
+        if err != nil || *got != tt.want {
+            t.Errorf("ParseRemoteURL(%q) = %+v, %v, want %+v", tt.url, got, err, tt.want)
+        }
+    }
+}
@@ -0,0 +1,142 @@
+package git
+
+import (
+    "strings"
+    "unicode"
+
+    "golang.org/x/text/unicode/norm"
+    "jira-tools/internal/config"
+)
+
+// transliterations covers letters that do not decompose into an ASCII base
+// letter plus combining marks, and non-Latin scripts with a conventional
+// romanisation.
+var transliterations = map[rune]string{
+    // Latin
+    'ß': "ss", 'ẞ': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe",
+    'ø': "o", 'Ø': "o", 'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d",
+    'þ': "th", 'Þ': "th", 'ł': "l", 'Ł': "l", 'ı': "i", 'ħ': "h",
+    'Ħ': "h", 'ŧ': "t", 'Ŧ': "t", 'ŋ': "ng", 'Ŋ': "ng", 'ſ': "s",
+    // Punctuation that should not split words
+    '\'': "", '’': "", '‘': "", '`': "", '&': " and ",
+    // Greek
+    'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+    'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+    'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+    'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+    // Cyrillic (Russian, Ukrainian, Bulgarian, Serbian)
+    'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+    'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+    'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+    'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+    'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+    'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ђ': "dj", 'ј': "j",
+    'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+}
+
+// lastStrippableRune ends the Latin, Greek and Cyrillic blocks.
+const lastStrippableRune = '\u04FF'
+
+var germanUmlauts = map[rune]string{
+    'ä': "ae", 'ö': "oe", 'ü': "ue", 'Ä': "ae", 'Ö': "oe", 'Ü': "ue",
+}
+
+// defaultStopWords are dropped when SlugConfig.RemoveStopWords is set.
+var defaultStopWords = []string{
+    // English
+    "a", "an", "and", "are", "as", "at", "be", "by", "for", "from", "in",
+    "into", "is", "it", "of", "on", "or", "the", "to", "with",
+    // Indonesian
+    "dan", "di", "ke", "dari", "yang", "untuk", "dengan", "pada", "atau",
+    "ini", "itu", "oleh",
+}
+
+// Slugify turns text into a lowercase, hyphen separated branch name
+// fragment, transliterating accented and non-Latin letters to ASCII. It
+// returns an empty string when nothing usable is left.
+func Slugify(text string, opts config.SlugConfig) string {
+    text = norm.NFC.String(text)
+    if opts.GermanUmlauts {
+        for umlaut, replacement := range germanUmlauts {
+            text = strings.ReplaceAll(text, string(umlaut), replacement)
+        }
+    }
+
+    // Look up the composed letters first, so й, ё and ї keep their own
+    // transliteration. The text between them loses its marks and is looked
+    // up again, turning ό into o.
+    var b, run strings.Builder
+    transliterate := func(text string) {
+        for _, r := range text {
+            if replacement, ok := transliterations[unicode.ToLower(r)]; ok {
+                b.WriteString(replacement)
+                continue
+            }
+            b.WriteRune(r)
+        }
+    }
+    for _, r := range text {
+        if _, ok := transliterations[unicode.ToLower(r)]; ok {
+            transliterate(stripMarks(run.String()))
+            run.Reset()
+            transliterate(string(r))
+            continue
+        }
+        run.WriteRune(r)
+    }
+    transliterate(stripMarks(run.String()))
+    ascii := strings.ToLower(b.String())
+
+    // Split into words on anything that is not kept
+    words := strings.FieldsFunc(ascii, func(r rune) bool {
+        if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+            return false
+        }
+        if opts.KeepUnicode && r > unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+            return false
+        }
+        return true
+    })
+
+    if opts.RemoveStopWords {
+        words = removeStopWords(words, opts.StopWords)
+    }
+    return strings.Join(words, "-")
+}
+
+func removeStopWords(words, extra []string) []string {
+    stop := make(map[string]bool, len(defaultStopWords)+len(extra))
+    for _, word := range defaultStopWords {
+        stop[word] = true
+    }
+    for _, word := range extra {
+        stop[strings.ToLower(word)] = true
+    }
+
+    var kept []string
+    for _, word := range words {
+        if !stop[word] {
+            kept = append(kept, word)
+        }
+    }
+    return kept
+}
+
+// stripMarks removes the combining marks of Latin, Greek and Cyrillic
+// letters after canonical decomposition, turning é into e and ạ into a.
+// Marks on other scripts are meaningful (e.g. Japanese dakuten) and kept.
+func stripMarks(text string) string {
+    var b strings.Builder
+    var base rune
+    for _, r := range norm.NFD.String(text) {
+        if unicode.Is(unicode.Mn, r) {
+            if base <= lastStrippableRune {
+                continue
+            }
+        } else {
+            base = r
+        }
+        b.WriteRune(r)
+    }
+    return norm.NFC.String(b.String())
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+    "testing"
+
+    "jira-tools/internal/config"
+)
+
+func TestSlugify(t *testing.T) {
+    tests := []struct {
+        name string
+        text string
+        opts config.SlugConfig
+        want string
+    }{
+        {
+            name: "indonesian",
+            text: "Perbaikan validasi formulir pendaftaran pengguna",
+            want: "perbaikan-validasi-formulir-pendaftaran-pengguna",
+        },
+        {
+            name: "german without umlauts",
+            text: "Größe der Übersicht ändern",
+            want: "grosse-der-ubersicht-andern",
+        },
+        {
+            name: "german with umlauts",
+            text: "Größe der Übersicht ändern",
+            opts: config.SlugConfig{GermanUmlauts: true},
+            want: "groesse-der-uebersicht-aendern",
+        },
+        {
+            name: "decomposed input",
+            text: "Cafe\u0301 menu\u0300",
+            want: "cafe-menu",
+        },
+        {
+            name: "russian",
+            text: "Настройка йогурт ёлка",
+            want: "nastroyka-yogurt-elka",
+        },
+        {
+            name: "ukrainian",
+            text: "Їжак і ґанок",
+            want: "yizhak-i-ganok",
+        },
+        {
+            name: "greek",
+            text: "Διόρθωση σύνδεσης",
+            want: "diorthosi-syndesis",
+        },
+        {
+            name: "cjk dropped",
+            text: "修复 login 页面",
+            want: "login",
+        },
+        {
+            name: "cjk kept",
+            text: "修复 login 页面",
+            opts: config.SlugConfig{KeepUnicode: true},
+            want: "修复-login-页面",
+        },
+        {
+            name: "stop words",
+            text: "Add the export to CSV dan perbaikan untuk laporan",
+            opts: config.SlugConfig{RemoveStopWords: true, StopWords: []string{"CSV"}},
+            want: "add-export-perbaikan-laporan",
+        },
+        {
+            name: "punctuation",
+            text: "Don't break R&D's \"quick\" fix!",
+            want: "dont-break-r-and-ds-quick-fix",
+        },
+        {
+            name: "nothing usable",
+            text: "修复",
+            want: "",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Slugify(tt.text, tt.opts); got != tt.want {
+                t.Errorf("Slugify(%q) = %q, want %q", tt.text, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestFormatBranchNameSlug(t *testing.T) {
+    tests := []struct {
+        name   string
+        config config.BranchConfig
+        issue  BranchIssue
+        want   string
+    }{
+        {
+            name:  "default template",
+            issue: BranchIssue{Key: "PROJ-1", Summary: "Add login page"},
+            want:  "feature/PROJ-1-add-login-page",
+        },
+        {
+            name:   "empty slug falls back to the key",
+            config: config.BranchConfig{BranchTemplate: "{{.Type}}/{{.Slug}}"},
+            issue:  BranchIssue{Key: "PROJ-2", Summary: "修复页面"},
+            want:   "feature/proj-2",
+        },
+        {
+            name:  "empty slug with the key in the template",
+            issue: BranchIssue{Key: "PROJ-3", Summary: "修复页面"},
+            want:  "feature/PROJ-3",
+        },
+        {
+            name:   "long summary drops whole words",
+            config: config.BranchConfig{BranchMaxLength: 30},
+            issue:  BranchIssue{Key: "PROJ-4", Summary: "Add a configurable retry policy to the uploader"},
+            want:   "feature/PROJ-4-add-a",
+        },
+        {
+            name:   "name without a slug is cut",
+            config: config.BranchConfig{BranchTemplate: "{{.Type}}/{{.Key}}-{{.Summary | lower}}", BranchMaxLength: 20},
+            issue:  BranchIssue{Key: "PROJ-5", Summary: "abcdefghijklmnop"},
+            want:   "feature/PROJ-5-abcde",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := FormatBranchName(&tt.config, tt.issue, FeatureBranch)
+            if err != nil {
+                t.Fatalf("FormatBranchName: %v", err)
+            }
+            if got != tt.want {
+                t.Errorf("FormatBranchName = %q, want %q", got, tt.want)
+            }
+            if tt.config.BranchMaxLength > 0 && len(got) > tt.config.BranchMaxLength {
+                t.Errorf("%q is longer than %d", got, tt.config.BranchMaxLength)
+            }
+        })
+    }
+}
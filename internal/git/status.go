@@ -0,0 +1,162 @@
+package git
+
+import (
+    "bytes"
+    "fmt"
+    "os/exec"
+    "strconv"
+    "strings"
+)
+
+// FileChange is a changed path in the working tree or index.
+type FileChange struct {
+    Path string
+    // Index and WorkTree are the two status letters of `git status --short`.
+    Index    byte
+    WorkTree byte
+    Added    int
+    Deleted  int
+    Binary   bool
+}
+
+// Staged reports whether the change has staged content.
+func (f FileChange) Staged() bool {
+    return f.Index != ' ' && f.Index != '?'
+}
+
+// Untracked reports whether the path is not known to git yet.
+func (f FileChange) Untracked() bool {
+    return f.Index == '?'
+}
+
+// GetChangedFiles lists modified, staged and untracked files with the
+// number of added and deleted lines.
+func GetChangedFiles() ([]FileChange, error) {
+    output, err := exec.Command("git", "status", "--porcelain", "-z", "--untracked-files=all").Output()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get status: %v", err)
+    }
+
+    var changes []FileChange
+    entries := strings.Split(string(output), "\x00")
+    for i := 0; i < len(entries); i++ {
+        entry := entries[i]
+        if len(entry) < 4 {
+            continue
+        }
+        change := FileChange{Index: entry[0], WorkTree: entry[1], Path: entry[3:]}
+        if change.Index == 'R' || change.Index == 'C' {
+            // The original path of a rename follows as its own entry
+            i++
+        }
+        changes = append(changes, change)
+    }
+
+    stats := make(map[string][2]int)
+    binary := make(map[string]bool)
+    for _, args := range [][]string{{"diff", "--numstat", "-z"}, {"diff", "--cached", "--numstat", "-z"}} {
+        output, err := exec.Command("git", args...).Output()
+        if err != nil {
+            return nil, fmt.Errorf("failed to get diff stats: %v", err)
+        }
+        parseNumstat(output, stats, binary)
+    }
+
+    for i := range changes {
+        stat := stats[changes[i].Path]
+        changes[i].Added, changes[i].Deleted = stat[0], stat[1]
+        changes[i].Binary = binary[changes[i].Path]
+    }
+    return changes, nil
+}
+
+// parseNumstat adds `git diff --numstat -z` output to stats, keyed by path.
+func parseNumstat(output []byte, stats map[string][2]int, binary map[string]bool) {
+    fields := bytes.Split(output, []byte{0})
+    for i := 0; i < len(fields); i++ {
+        parts := strings.SplitN(string(fields[i]), "\t", 3)
+        if len(parts) != 3 {
+            continue
+        }
+        path := parts[2]
+        if path == "" && i+2 < len(fields) {
+            // Renames list the old and new path as separate fields
+            path = string(fields[i+2])
+            i += 2
+        }
+        if parts[0] == "-" {
+            binary[path] = true
+            continue
+        }
+        added, _ := strconv.Atoi(parts[0])
+        deleted, _ := strconv.Atoi(parts[1])
+        stat := stats[path]
+        stats[path] = [2]int{stat[0] + added, stat[1] + deleted}
+    }
+}
+
+// StagePathspecs stages paths or patterns given on the command line,
+// relative to the current directory.
+func StagePathspecs(pathspecs []string) error {
+    if len(pathspecs) == 0 {
+        return nil
+    }
+    args := append([]string{"add", "--"}, pathspecs...)
+    if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+        return fmt.Errorf("failed to stage changes: %s", commandError(output, err))
+    }
+    return nil
+}
+
+// StagePaths stages the given paths, relative to the repository root like
+// the paths of GetChangedFiles.
+func StagePaths(paths []string) error {
+    if len(paths) == 0 {
+        return nil
+    }
+    args := append([]string{"add", "--"}, topPathspecs(paths)...)
+    if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+        return fmt.Errorf("failed to stage changes: %s", commandError(output, err))
+    }
+    return nil
+}
+
+// UnstagePaths removes the given paths, relative to the repository root,
+// from the index, keeping the changes in the working tree.
+func UnstagePaths(paths []string) error {
+    if len(paths) == 0 {
+        return nil
+    }
+    args := append([]string{"reset", "-q", "--"}, topPathspecs(paths)...)
+    if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+        return fmt.Errorf("failed to unstage changes: %s", commandError(output, err))
+    }
+    return nil
+}
+
+// topPathspecs anchors paths at the repository root, so they match from a
+// subdirectory too, and keeps git from expanding wildcards in file names.
+func topPathspecs(paths []string) []string {
+    pathspecs := make([]string, len(paths))
+    for i, path := range paths {
+        pathspecs[i] = ":(top,literal)" + path
+    }
+    return pathspecs
+}
+
+// GetStatusSummary returns `git status --short --branch` output.
+func GetStatusSummary() (string, error) {
+    output, err := exec.Command("git", "status", "--short", "--branch").Output()
+    if err != nil {
+        return "", fmt.Errorf("failed to get status: %v", err)
+    }
+    return strings.TrimRight(string(output), "\n"), nil
+}
+
+// commandError prefers git's own message over the bare exit status.
+func commandError(output []byte, err error) string {
+    if message := strings.TrimSpace(string(output)); message != "" {
+        return message
+    }
+    return err.Error()
+}
@@ -0,0 +1,125 @@
+package git
+
+import (
+    "os"
+    "os/exec"
+    "path/filepath"
+    "sort"
+    "strings"
+    "testing"
+)
+
+// chdirToNewRepo creates an empty repository and changes into it for the
+// rest of the test.
+func chdirToNewRepo(t *testing.T) string {
+    t.Helper()
+    root := t.TempDir()
+    chdir(t, root)
+    runGit(t, "init", "-q")
+    runGit(t, "config", "user.email", "dev@example.com")
+    runGit(t, "config", "user.name", "Dev")
+    return root
+}
+
+// chdir changes into dir until the end of the test.
+func chdir(t *testing.T, dir string) {
+    t.Helper()
+    wd, err := os.Getwd()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Chdir(dir); err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// runGit runs a git command that has to succeed and returns its output.
+func runGit(t *testing.T, args ...string) string {
+    t.Helper()
+    output, err := exec.Command("git", args...).CombinedOutput()
+    if err != nil {
+        t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+    }
+    return strings.TrimSpace(string(output))
+}
+
+func writeFile(t *testing.T, path, content string) {
+    t.Helper()
+    if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestStagePathsFromSubdirectory(t *testing.T) {
+    root := chdirToNewRepo(t)
+    if err := os.Mkdir("sub", 0755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Chdir("sub"); err != nil {
+        t.Fatal(err)
+    }
+    writeFile(t, filepath.Join(root, "top.txt"), "top\n")
+    writeFile(t, filepath.Join(root, "sub", "in.txt"), "in\n")
+    writeFile(t, filepath.Join(root, "sub", "w*.txt"), "wildcard\n")
+    writeFile(t, filepath.Join(root, "sub", "w1.txt"), "not selected\n")
+
+    changes, err := GetChangedFiles()
+    if err != nil {
+        t.Fatalf("GetChangedFiles: %v", err)
+    }
+    var paths []string
+    for _, change := range changes {
+        paths = append(paths, change.Path)
+    }
+    sort.Strings(paths)
+    if want := []string{"sub/in.txt", "sub/w*.txt", "sub/w1.txt", "top.txt"}; !equalStrings(paths, want) {
+        t.Fatalf("changed paths = %v, want %v relative to the root", paths, want)
+    }
+
+    if err := StagePaths([]string{"top.txt", "sub/in.txt", "sub/w*.txt"}); err != nil {
+        t.Fatalf("StagePaths: %v", err)
+    }
+    staged, err := GetStagedFiles()
+    if err != nil {
+        t.Fatalf("GetStagedFiles: %v", err)
+    }
+    if want := []string{"sub/in.txt", "sub/w*.txt", "top.txt"}; !equalStrings(staged, want) {
+        t.Errorf("staged = %v, want %v", staged, want)
+    }
+
+    if err := UnstagePaths([]string{"top.txt"}); err != nil {
+        t.Fatalf("UnstagePaths: %v", err)
+    }
+    staged, err = GetStagedFiles()
+    if err != nil {
+        t.Fatalf("GetStagedFiles: %v", err)
+    }
+    if want := []string{"sub/in.txt", "sub/w*.txt"}; !equalStrings(staged, want) {
+        t.Errorf("staged after unstaging top.txt = %v, want %v", staged, want)
+    }
+
+    // Command line paths stay relative to the current directory
+    if err := StagePathspecs([]string{"w1.txt"}); err != nil {
+        t.Fatalf("StagePathspecs: %v", err)
+    }
+    staged, err = GetStagedFiles()
+    if err != nil {
+        t.Fatalf("GetStagedFiles: %v", err)
+    }
+    if want := []string{"sub/in.txt", "sub/w*.txt", "sub/w1.txt"}; !equalStrings(staged, want) {
+        t.Errorf("staged after StagePathspecs = %v, want %v", staged, want)
+    }
+}
+
+func equalStrings(a, b []string) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for i := range a {
+        if a[i] != b[i] {
+            return false
+        }
+    }
+    return true
+}
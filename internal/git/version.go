@@ -0,0 +1,115 @@
+package git
+
+import (
+    "fmt"
+    "os/exec"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+var versionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)$`)
+
+// Version is a major.minor.patch release number.
+type Version struct {
+    Major, Minor, Patch int
+}
+
+// ParseVersion parses "1.2.3" or "v1.2.3".
+func ParseVersion(value string) (Version, error) {
+    match := versionPattern.FindStringSubmatch(strings.TrimSpace(value))
+    if match == nil {
+        return Version{}, fmt.Errorf("invalid version %q, expected MAJOR.MINOR.PATCH", value)
+    }
+    major, _ := strconv.Atoi(match[1])
+    minor, _ := strconv.Atoi(match[2])
+    patch, _ := strconv.Atoi(match[3])
+    return Version{Major: major, Minor: minor, Patch: patch}, nil
+}
+
+func (v Version) String() string {
+    return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less reports whether v is an older version than other.
+func (v Version) Less(other Version) bool {
+    if v.Major != other.Major {
+        return v.Major < other.Major
+    }
+    if v.Minor != other.Minor {
+        return v.Minor < other.Minor
+    }
+    return v.Patch < other.Patch
+}
+
+// Bump returns the next version for part, which is "major", "minor" or
+// "patch".
+func (v Version) Bump(part string) (Version, error) {
+    switch part {
+    case "major":
+        return Version{Major: v.Major + 1}, nil
+    case "minor":
+        return Version{Major: v.Major, Minor: v.Minor + 1}, nil
+    case "patch":
+        return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}, nil
+    default:
+        return Version{}, fmt.Errorf("invalid version part %q, expected major, minor or patch", part)
+    }
+}
+
+// VersionTag is a tag that names a version, such as v1.4.2.
+type VersionTag struct {
+    Name    string
+    Version Version
+}
+
+// VersionTags returns the tags made of prefix and a version, oldest
+// version first.
+func VersionTags(prefix string) ([]VersionTag, error) {
+    output, err := exec.Command("git", "tag", "--list", prefix+"*").Output()
+    if err != nil {
+        return nil, fmt.Errorf("failed to list tags: %v", err)
+    }
+
+    var tags []VersionTag
+    for _, line := range strings.Split(string(output), "\n") {
+        name := strings.TrimSpace(line)
+        if name == "" {
+            continue
+        }
+        version, err := ParseVersion(strings.TrimPrefix(name, prefix))
+        if err != nil {
+            continue
+        }
+        tags = append(tags, VersionTag{Name: name, Version: version})
+    }
+    sort.Slice(tags, func(i, j int) bool {
+        return tags[i].Version.Less(tags[j].Version)
+    })
+    return tags, nil
+}
+
+// LatestVersionTag returns the highest version tag, or nil when nothing
+// has been tagged yet.
+func LatestVersionTag(prefix string) (*VersionTag, error) {
+    tags, err := VersionTags(prefix)
+    if err != nil || len(tags) == 0 {
+        return nil, err
+    }
+    return &tags[len(tags)-1], nil
+}
+
+// PreviousVersionTag returns the highest version tag below version, or nil.
+func PreviousVersionTag(prefix string, version Version) (*VersionTag, error) {
+    tags, err := VersionTags(prefix)
+    if err != nil {
+        return nil, err
+    }
+    for i := len(tags) - 1; i >= 0; i-- {
+        if tags[i].Version.Less(version) {
+            return &tags[i], nil
+        }
+    }
+    return nil, nil
+}
@@ -0,0 +1,105 @@
+package git
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+    tests := []struct {
+        in      string
+        want    Version
+        wantErr bool
+    }{
+        {in: "1.2.3", want: Version{1, 2, 3}},
+        {in: "v1.2.3", want: Version{1, 2, 3}},
+        {in: " v10.0.12 ", want: Version{10, 0, 12}},
+        {in: "0.0.0", want: Version{}},
+        {in: "1.2", wantErr: true},
+        {in: "1.2.3.4", wantErr: true},
+        {in: "V1.2.3", wantErr: true},
+        {in: "release-1.2.3", wantErr: true},
+        {in: "1.2.3-rc.1", wantErr: true},
+        {in: "1.2.3+build.5", wantErr: true},
+        {in: "", wantErr: true},
+    }
+    for _, tt := range tests {
+        got, err := ParseVersion(tt.in)
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("ParseVersion(%q) = %v, want an error", tt.in, got)
+            }
+            continue
+        }
+        if err != nil || got != tt.want {
+            t.Errorf("ParseVersion(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+        }
+    }
+}
+
+func TestVersionBump(t *testing.T) {
+    v := Version{1, 4, 2}
+    tests := []struct {
+        part    string
+        want    string
+        wantErr bool
+    }{
+        {part: "major", want: "2.0.0"},
+        {part: "minor", want: "1.5.0"},
+        {part: "patch", want: "1.4.3"},
+        {part: "build", wantErr: true},
+        {part: "", wantErr: true},
+    }
+    for _, tt := range tests {
+        got, err := v.Bump(tt.part)
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("Bump(%q) = %v, want an error", tt.part, got)
+            }
+            continue
+        }
+        if err != nil || got.String() != tt.want {
+            t.Errorf("Bump(%q) = %v, %v, want %s", tt.part, got, err, tt.want)
+        }
+    }
+}
+
+func TestVersionTags(t *testing.T) {
+    chdirToNewRepo(t)
+    runGit(t, "commit", "-q", "--allow-empty", "-m", "init")
+    for _, tag := range []string{"v1.2.0", "v1.10.0", "v1.9.1", "v2.0.0-rc.1", "vnext", "release-3.0.0", "1.0.0"} {
+        runGit(t, "tag", tag)
+    }
+
+    tests := []struct {
+        prefix   string
+        tags     []string
+        previous string
+    }{
+        {prefix: "v", tags: []string{"v1.2.0", "v1.9.1", "v1.10.0"}, previous: "v1.9.1"},
+        {prefix: "release-", tags: []string{"release-3.0.0"}},
+        {prefix: "", tags: []string{"1.0.0", "v1.2.0", "v1.9.1", "v1.10.0"}, previous: "v1.9.1"},
+    }
+    for _, tt := range tests {
+        tags, err := VersionTags(tt.prefix)
+        if err != nil {
+            t.Fatalf("VersionTags(%q): %v", tt.prefix, err)
+        }
+        var names []string
+        for _, tag := range tags {
+            names = append(names, tag.Name)
+        }
+        if !equalStrings(names, tt.tags) {
+            t.Errorf("VersionTags(%q) = %v, want %v", tt.prefix, names, tt.tags)
+        }
+
+        previous, err := PreviousVersionTag(tt.prefix, Version{1, 10, 0})
+        if err != nil {
+            t.Fatalf("PreviousVersionTag(%q): %v", tt.prefix, err)
+        }
+        got := ""
+        if previous != nil {
+            got = previous.Name
+        }
+        if got != tt.previous {
+            t.Errorf("PreviousVersionTag(%q, 1.10.0) = %q, want %q", tt.prefix, got, tt.previous)
+        }
+    }
+}
@@ -0,0 +1,37 @@
+package hosting
+
+// bitbucketProvider opens pull requests through the Bitbucket Cloud REST
+// API (2.0).
+type bitbucketProvider struct {
+    api        *apiClient
+    repository string
+}
+
+func (p *bitbucketProvider) Name() string {
+    return "Bitbucket pull request"
+}
+
+func (p *bitbucketProvider) CreatePullRequest(pr PullRequest) (*Created, error) {
+    branch := func(name string) map[string]interface{} {
+        return map[string]interface{}{"branch": map[string]string{"name": name}}
+    }
+    body := map[string]interface{}{
+        "title":       pr.Title,
+        "description": pr.Body,
+        "source":      branch(pr.Head),
+        "destination": branch(pr.Base),
+        "draft":       pr.Draft,
+    }
+    var resp struct {
+        ID    int `json:"id"`
+        Links struct {
+            HTML struct {
+                Href string `json:"href"`
+            } `json:"html"`
+        } `json:"links"`
+    }
+    if err := p.api.post("/repositories/"+p.repository+"/pullrequests", body, &resp); err != nil {
+        return nil, err
+    }
+    return &Created{Number: resp.ID, URL: resp.Links.HTML.Href}, nil
+}
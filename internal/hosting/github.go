@@ -0,0 +1,30 @@
+package hosting
+
+// githubProvider opens pull requests through the GitHub REST API, including
+// GitHub Enterprise when APIURL points at https://<host>/api/v3.
+type githubProvider struct {
+    api        *apiClient
+    repository string
+}
+
+func (p *githubProvider) Name() string {
+    return "GitHub pull request"
+}
+
+func (p *githubProvider) CreatePullRequest(pr PullRequest) (*Created, error) {
+    body := map[string]interface{}{
+        "title": pr.Title,
+        "body":  pr.Body,
+        "head":  pr.Head,
+        "base":  pr.Base,
+        "draft": pr.Draft,
+    }
+    var resp struct {
+        Number  int    `json:"number"`
+        HTMLURL string `json:"html_url"`
+    }
+    if err := p.api.post("/repos/"+p.repository+"/pulls", body, &resp); err != nil {
+        return nil, err
+    }
+    return &Created{Number: resp.Number, URL: resp.HTMLURL}, nil
+}
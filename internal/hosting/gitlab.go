@@ -0,0 +1,36 @@
+package hosting
+
+import "net/url"
+
+// gitlabProvider opens merge requests through the GitLab REST API (v4).
+type gitlabProvider struct {
+    api        *apiClient
+    repository string
+}
+
+func (p *gitlabProvider) Name() string {
+    return "GitLab merge request"
+}
+
+func (p *gitlabProvider) CreatePullRequest(pr PullRequest) (*Created, error) {
+    title := pr.Title
+    if pr.Draft {
+        title = "Draft: " + title
+    }
+    body := map[string]interface{}{
+        "title":         title,
+        "description":   pr.Body,
+        "source_branch": pr.Head,
+        "target_branch": pr.Base,
+    }
+    var resp struct {
+        IID    int    `json:"iid"`
+        WebURL string `json:"web_url"`
+    }
+    // Projects are addressed by their URL-encoded namespace path
+    path := "/projects/" + url.PathEscape(p.repository) + "/merge_requests"
+    if err := p.api.post(path, body, &resp); err != nil {
+        return nil, err
+    }
+    return &Created{Number: resp.IID, URL: resp.WebURL}, nil
+}
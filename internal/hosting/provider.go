@@ -0,0 +1,218 @@
+package hosting
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "sort"
+    "strings"
+    "time"
+)
+
+// Supported hosting services.
+const (
+    GitHub    = "github"
+    GitLab    = "gitlab"
+    Bitbucket = "bitbucket"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// PullRequest is a pull request (merge request on GitLab) to open.
+type PullRequest struct {
+    Title string
+    Body  string
+    // Head is the branch with the changes, Base the branch to merge into.
+    Head  string
+    Base  string
+    Draft bool
+}
+
+// Created identifies an opened pull request.
+type Created struct {
+    Number int
+    URL    string
+}
+
+// Provider opens pull requests on a hosting service.
+type Provider interface {
+    // Name describes what the provider opens, e.g. "GitLab merge request".
+    Name() string
+    CreatePullRequest(pr PullRequest) (*Created, error)
+}
+
+// Config selects and configures a Provider.
+type Config struct {
+    // Kind is GitHub, GitLab or Bitbucket.
+    Kind string
+    // APIURL is the REST API root. Empty means the public service, or
+    // https://<host>/api/v3 for GitHub Enterprise and https://<host>/api/v4
+    // for self-hosted GitLab.
+    APIURL string
+    // Host is the web host of the repository, used to find self-hosted APIs.
+    Host string
+    // Repository is the owner/name path of the repository.
+    Repository string
+    Token      string
+    // Username switches Bitbucket to basic auth with Token as app password.
+    Username   string
+    HTTPClient *http.Client
+}
+
+// NewProvider returns the provider for cfg.Kind.
+func NewProvider(cfg Config) (Provider, error) {
+    if cfg.Repository == "" {
+        return nil, fmt.Errorf("repository cannot be empty")
+    }
+    if cfg.Token == "" {
+        return nil, fmt.Errorf("no %s token configured", cfg.Kind)
+    }
+    client := cfg.HTTPClient
+    if client == nil {
+        client = &http.Client{Timeout: defaultTimeout}
+    }
+    api := &apiClient{httpClient: client, baseURL: strings.TrimRight(cfg.APIURL, "/")}
+
+    switch cfg.Kind {
+    case GitHub:
+        if api.baseURL == "" {
+            api.baseURL = "https://api.github.com"
+            if cfg.Host != "" && cfg.Host != "github.com" {
+                api.baseURL = "https://" + cfg.Host + "/api/v3"
+            }
+        }
+        api.headers = map[string]string{
+            "Authorization": "Bearer " + cfg.Token,
+            "Accept":        "application/vnd.github+json",
+        }
+        return &githubProvider{api: api, repository: cfg.Repository}, nil
+    case GitLab:
+        if api.baseURL == "" {
+            host := cfg.Host
+            if host == "" {
+                host = "gitlab.com"
+            }
+            api.baseURL = "https://" + host + "/api/v4"
+        }
+        api.headers = map[string]string{"PRIVATE-TOKEN": cfg.Token}
+        return &gitlabProvider{api: api, repository: cfg.Repository}, nil
+    case Bitbucket:
+        if api.baseURL == "" {
+            api.baseURL = "https://api.bitbucket.org/2.0"
+        }
+        api.headers = map[string]string{"Authorization": "Bearer " + cfg.Token}
+        if cfg.Username != "" {
+            api.username, api.password = cfg.Username, cfg.Token
+            api.headers = nil
+        }
+        return &bitbucketProvider{api: api, repository: cfg.Repository}, nil
+    default:
+        return nil, fmt.Errorf("unsupported provider %q, expected %s, %s or %s", cfg.Kind, GitHub, GitLab, Bitbucket)
+    }
+}
+
+// DetectKind guesses the hosting service from the host of a remote.
+func DetectKind(host string) string {
+    switch {
+    case strings.Contains(host, "github"):
+        return GitHub
+    case strings.Contains(host, "gitlab"):
+        return GitLab
+    case strings.Contains(host, "bitbucket"):
+        return Bitbucket
+    }
+    return ""
+}
+
+// apiClient sends JSON requests to a hosting REST API.
+type apiClient struct {
+    httpClient         *http.Client
+    baseURL            string
+    headers            map[string]string
+    username, password string
+}
+
+// post sends body as JSON and decodes the JSON response into out.
+func (c *apiClient) post(path string, body, out interface{}) error {
+    data, err := json.Marshal(body)
+    if err != nil {
+        return err
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+    defer cancel()
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(data))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("User-Agent", "jira-tools")
+    for name, value := range c.headers {
+        req.Header.Set(name, value)
+    }
+    if c.username != "" {
+        req.SetBasicAuth(c.username, c.password)
+    }
+
+    resp, err := c.httpClient.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    respBody, _ := io.ReadAll(resp.Body)
+    if resp.StatusCode < 200 || resp.StatusCode > 299 {
+        return fmt.Errorf("HTTP %d: %s", resp.StatusCode, errorMessage(respBody))
+    }
+    return json.Unmarshal(respBody, out)
+}
+
+// errorMessage extracts the message from the error payloads of the
+// supported services, falling back to the raw body.
+func errorMessage(body []byte) string {
+    var payload struct {
+        Message interface{} `json:"message"`
+        Errors  []struct {
+            Message string `json:"message"`
+        } `json:"errors"`
+        Error struct {
+            Message string `json:"message"`
+        } `json:"error"`
+    }
+    if json.Unmarshal(body, &payload) == nil {
+        var messages []string
+        switch message := payload.Message.(type) {
+        case string:
+            messages = append(messages, message)
+        case []interface{}:
+            for _, m := range message {
+                messages = append(messages, fmt.Sprint(m))
+            }
+        case map[string]interface{}:
+            fields := make([]string, 0, len(message))
+            for field := range message {
+                fields = append(fields, field)
+            }
+            sort.Strings(fields)
+            for _, field := range fields {
+                messages = append(messages, fmt.Sprintf("%s: %v", field, message[field]))
+            }
+        }
+        for _, e := range payload.Errors {
+            if e.Message != "" {
+                messages = append(messages, e.Message)
+            }
+        }
+        if payload.Error.Message != "" {
+            messages = append(messages, payload.Error.Message)
+        }
+        if len(messages) > 0 {
+            return strings.Join(messages, "; ")
+        }
+    }
+    return strings.TrimSpace(string(body))
+}
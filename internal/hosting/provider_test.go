@@ -0,0 +1,226 @@
+package hosting
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestCreatePullRequest(t *testing.T) {
+    pr := PullRequest{
+        Title: "PROJ-1 Add login",
+        Body:  "Closes PROJ-1",
+        Head:  "feature/PROJ-1-add-login",
+        Base:  "develop",
+        Draft: true,
+    }
+    tests := []struct {
+        name     string
+        kind     string
+        username string
+        path     string
+        header   string
+        value    string
+        response string
+        body     map[string]interface{}
+        want     Created
+    }{
+        {
+            name:     "github",
+            kind:     GitHub,
+            path:     "/repos/acme/app/pulls",
+            header:   "Authorization",
+            value:    "Bearer secret",
+            response: `{"number":12,"html_url":"https://github.com/acme/app/pull/12"}`,
+            body: map[string]interface{}{
+                "title": "PROJ-1 Add login",
+                "body":  "Closes PROJ-1",
+                "head":  "feature/PROJ-1-add-login",
+                "base":  "develop",
+                "draft": true,
+            },
+            want: Created{Number: 12, URL: "https://github.com/acme/app/pull/12"},
+        },
+        {
+            name:     "gitlab",
+            kind:     GitLab,
+            path:     "/projects/acme%2Fapp/merge_requests",
+            header:   "PRIVATE-TOKEN",
+            value:    "secret",
+            response: `{"iid":7,"web_url":"https://gitlab.com/acme/app/-/merge_requests/7"}`,
+            body: map[string]interface{}{
+                "title":         "Draft: PROJ-1 Add login",
+                "description":   "Closes PROJ-1",
+                "source_branch": "feature/PROJ-1-add-login",
+                "target_branch": "develop",
+            },
+            want: Created{Number: 7, URL: "https://gitlab.com/acme/app/-/merge_requests/7"},
+        },
+        {
+            name:     "bitbucket app password",
+            kind:     Bitbucket,
+            username: "dev",
+            path:     "/repositories/acme/app/pullrequests",
+            header:   "Authorization",
+            value:    "Basic ZGV2OnNlY3JldA==",
+            response: `{"id":3,"links":{"html":{"href":"https://bitbucket.org/acme/app/pull-requests/3"}}}`,
+            body: map[string]interface{}{
+                "title":       "PROJ-1 Add login",
+                "description": "Closes PROJ-1",
+                "source":      map[string]interface{}{"branch": map[string]interface{}{"name": "feature/PROJ-1-add-login"}},
+                "destination": map[string]interface{}{"branch": map[string]interface{}{"name": "develop"}},
+                "draft":       true,
+            },
+            want: Created{Number: 3, URL: "https://bitbucket.org/acme/app/pull-requests/3"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodPost || r.URL.EscapedPath() != tt.path {
+                    t.Errorf("request = %s %s, want POST %s", r.Method, r.URL.EscapedPath(), tt.path)
+                }
+                if got := r.Header.Get(tt.header); got != tt.value {
+                    t.Errorf("%s = %q, want %q", tt.header, got, tt.value)
+                }
+                var body map[string]interface{}
+                if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                    t.Fatalf("decoding request body: %v", err)
+                }
+                if !reflect.DeepEqual(body, tt.body) {
+                    t.Errorf("body = %v\nwant %v", body, tt.body)
+                }
+                w.WriteHeader(http.StatusCreated)
+                w.Write([]byte(tt.response))
+            }))
+            defer server.Close()
+
+            provider, err := NewProvider(Config{
+                Kind:       tt.kind,
+                APIURL:     server.URL + "/",
+                Repository: "acme/app",
+                Token:      "secret",
+                Username:   tt.username,
+            })
+            if err != nil {
+                t.Fatalf("NewProvider: %v", err)
+            }
+            created, err := provider.CreatePullRequest(pr)
+            if err != nil {
+                t.Fatalf("CreatePullRequest: %v", err)
+            }
+            if *created != tt.want {
+                t.Errorf("created = %+v, want %+v", *created, tt.want)
+            }
+        })
+    }
+}
+
+func TestCreatePullRequestAlreadyExists(t *testing.T) {
+    tests := []struct {
+        name     string
+        kind     string
+        status   int
+        response string
+        want     string
+    }{
+        {
+            name:     "github",
+            kind:     GitHub,
+            status:   http.StatusUnprocessableEntity,
+            response: `{"message":"Validation Failed","errors":[{"resource":"PullRequest","code":"custom","message":"A pull request already exists for acme:feature/PROJ-1."}]}`,
+            want:     "HTTP 422: Validation Failed; A pull request already exists for acme:feature/PROJ-1.",
+        },
+        {
+            name:     "gitlab",
+            kind:     GitLab,
+            status:   http.StatusConflict,
+            response: `{"message":["Another open merge request already exists for this source branch: !7"]}`,
+            want:     "HTTP 409: Another open merge request already exists for this source branch: !7",
+        },
+        {
+            name:     "bitbucket",
+            kind:     Bitbucket,
+            status:   http.StatusBadRequest,
+            response: `{"type":"error","error":{"message":"There is already an open pull request from feature/PROJ-1 to develop"}}`,
+            want:     "HTTP 400: There is already an open pull request from feature/PROJ-1 to develop",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                w.WriteHeader(tt.status)
+                w.Write([]byte(tt.response))
+            }))
+            defer server.Close()
+
+            provider, err := NewProvider(Config{Kind: tt.kind, APIURL: server.URL, Repository: "acme/app", Token: "secret"})
+            if err != nil {
+                t.Fatalf("NewProvider: %v", err)
+            }
+            created, err := provider.CreatePullRequest(PullRequest{Title: "PROJ-1", Head: "feature/PROJ-1", Base: "develop"})
+            if err == nil {
+                t.Fatalf("CreatePullRequest = %+v, want an error", created)
+            }
+            if err.Error() != tt.want {
+                t.Errorf("error = %q, want %q", err, tt.want)
+            }
+        })
+    }
+}
+
+func TestProviderAPIURL(t *testing.T) {
+    tests := []struct {
+        name string
+        cfg  Config
+        want string
+    }{
+        {name: "github.com", cfg: Config{Kind: GitHub, Host: "github.com"}, want: "https://api.github.com"},
+        {name: "github without host", cfg: Config{Kind: GitHub}, want: "https://api.github.com"},
+        {name: "github enterprise", cfg: Config{Kind: GitHub, Host: "github.example.com"}, want: "https://github.example.com/api/v3"},
+        {name: "configured api url", cfg: Config{Kind: GitHub, Host: "github.example.com", APIURL: "https://api.example.com/"}, want: "https://api.example.com"},
+        {name: "gitlab.com", cfg: Config{Kind: GitLab}, want: "https://gitlab.com/api/v4"},
+        {name: "self-hosted gitlab", cfg: Config{Kind: GitLab, Host: "git.example.com"}, want: "https://git.example.com/api/v4"},
+        {name: "bitbucket", cfg: Config{Kind: Bitbucket, Host: "bitbucket.org"}, want: "https://api.bitbucket.org/2.0"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            tt.cfg.Repository, tt.cfg.Token = "acme/app", "secret"
+            provider, err := NewProvider(tt.cfg)
+            if err != nil {
+                t.Fatalf("NewProvider: %v", err)
+            }
+            var api *apiClient
+            switch p := provider.(type) {
+            case *githubProvider:
+                api = p.api
+            case *gitlabProvider:
+                api = p.api
+            case *bitbucketProvider:
+                api = p.api
+            }
+            if api.baseURL != tt.want {
+                t.Errorf("API URL = %q, want %q", api.baseURL, tt.want)
+            }
+        })
+    }
+}
+
+func TestNewProviderErrors(t *testing.T) {
+    tests := []struct {
+        cfg  Config
+        want string
+    }{
+        {Config{Kind: GitHub, Token: "secret"}, "repository cannot be empty"},
+        {Config{Kind: GitLab, Repository: "acme/app"}, "no gitlab token configured"},
+        {Config{Kind: "gitea", Repository: "acme/app", Token: "secret"}, "unsupported provider"},
+    }
+    for _, tt := range tests {
+        if _, err := NewProvider(tt.cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+            t.Errorf("NewProvider(%+v) error = %v, want %q", tt.cfg, err, tt.want)
+        }
+    }
+}
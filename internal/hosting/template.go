@@ -0,0 +1,83 @@
+package hosting
+
+import (
+    "bytes"
+    "fmt"
+    "strings"
+    "text/template"
+
+    "jira-tools/internal/config"
+)
+
+// DefaultTitleTemplate produces "PROJ-123: summary".
+const DefaultTitleTemplate = "{{.Key}}: {{.Summary}}"
+
+// DefaultBodyTemplate links the issue and quotes its description and the
+// commits of the branch.
+const DefaultBodyTemplate = `[{{.Key}}]({{.URL}}): {{.Summary}}
+{{if .Description}}
+## Description
+
+{{.Description}}
+{{end}}{{if .Commits}}
+## Commits
+
+{{range .Commits}}- {{.}}
+{{end}}{{end}}`
+
+// TemplateData is the data pull request templates are executed with.
+type TemplateData struct {
+    Key         string
+    Summary     string
+    Description string
+    URL         string
+    IssueType   string
+    Status      string
+    Branch      string
+    Base        string
+    // Commits are the subjects of the commits on the branch, oldest first.
+    Commits []string
+}
+
+var templateFuncs = template.FuncMap{
+    "lower": strings.ToLower,
+    "upper": strings.ToUpper,
+    "join":  strings.Join,
+    "trim":  strings.TrimSpace,
+}
+
+// FormatPullRequest renders the title and body templates of the project.
+func FormatPullRequest(cfg config.PullRequestConfig, data TemplateData) (title, body string, err error) {
+    titleText, bodyText := DefaultTitleTemplate, DefaultBodyTemplate
+    if cfg.TitleTemplate != "" {
+        titleText = cfg.TitleTemplate
+    }
+    if cfg.BodyTemplate != "" {
+        bodyText = cfg.BodyTemplate
+    }
+
+    if title, err = render("title", titleText, data); err != nil {
+        return "", "", err
+    }
+    if body, err = render("body", bodyText, data); err != nil {
+        return "", "", err
+    }
+    title = strings.Join(strings.Fields(title), " ")
+    if title == "" {
+        return "", "", fmt.Errorf("pull request title template produced an empty title")
+    }
+    return title, body, nil
+}
+
+func render(name, text string, data TemplateData) (string, error) {
+    tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+    if err != nil {
+        return "", fmt.Errorf("invalid pull request %s template: %v", name, err)
+    }
+
+    var buf bytes.Buffer
+    if err := tmpl.Execute(&buf, data); err != nil {
+        return "", fmt.Errorf("failed to render pull request %s template: %v", name, err)
+    }
+    return strings.TrimSpace(buf.String()) + "\n", nil
+}
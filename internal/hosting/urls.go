@@ -0,0 +1,50 @@
+package hosting
+
+import (
+    "net/url"
+    "strings"
+
+    "jira-tools/internal/git"
+)
+
+// ServiceName returns the display name of a hosting service.
+func ServiceName(kind string) string {
+    switch kind {
+    case GitHub:
+        return "GitHub"
+    case GitLab:
+        return "GitLab"
+    case Bitbucket:
+        return "Bitbucket"
+    }
+    return "Git"
+}
+
+// RepositoryURL returns the web page of the repository behind a remote,
+// e.g. https://github.com/acme/api for git@github.com:acme/api.git.
+func RepositoryURL(remote *git.Remote) string {
+    return "https://" + remote.Host + "/" + remote.Path
+}
+
+// BranchURL returns the web page of a branch in the repository at repoURL.
+func BranchURL(kind, repoURL, branch string) string {
+    escaped := escapeBranch(branch)
+    switch kind {
+    case GitLab:
+        return repoURL + "/-/tree/" + escaped
+    case Bitbucket:
+        return repoURL + "/branch/" + escaped
+    default:
+        return repoURL + "/tree/" + escaped
+    }
+}
+
+// escapeBranch escapes each path segment of a branch name, keeping the
+// slashes of feature/PROJ-1-x readable.
+func escapeBranch(branch string) string {
+    segments := strings.Split(branch, "/")
+    for i, segment := range segments {
+        segments[i] = url.PathEscape(segment)
+    }
+    return strings.Join(segments, "/")
+}
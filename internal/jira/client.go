@@ -0,0 +1,306 @@
+package jira
+
+import (
+    "bytes"
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "os"
+    "sort"
+    "strings"
+    "time"
+)
+
+const (
+    DefaultTimeout   = 30 * time.Second
+    DefaultUserAgent = "jira-tools"
+    // DefaultAPIVersion is the REST API version used for rich text fields.
+    DefaultAPIVersion = "2"
+)
+
+// Auth decorates outgoing requests with credentials.
+type Auth interface {
+    Apply(req *http.Request) error
+}
+
+// BasicAuth authenticates with an account email and API token (Jira Cloud),
+// or with a username and password on Jira Server and Data Center.
+type BasicAuth struct {
+    Email    string
+    APIToken string
+}
+
+func (a BasicAuth) Apply(req *http.Request) error {
+    auth := a.Email + ":" + a.APIToken
+    encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
+    req.Header.Set("Authorization", "Basic "+encodedAuth)
+    return nil
+}
+
+// BearerAuth authenticates with a personal access token (Jira Server and
+// Data Center 8.14 and later).
+type BearerAuth struct {
+    Token string
+}
+
+func (a BearerAuth) Apply(req *http.Request) error {
+    req.Header.Set("Authorization", "Bearer "+a.Token)
+    return nil
+}
+
+// Options configures a Client. Only BaseURL is required.
+type Options struct {
+    // BaseURL is the Jira site, e.g. https://company.atlassian.net or
+    // https://jira.example.com/jira for a server under a context path. A
+    // bare domain is accepted and assumed to be served over https.
+    BaseURL string
+    // APIURL, when set, receives the REST requests instead of BaseURL, as
+    // the api.atlassian.com gateway does for OAuth 2.0. Browse links still
+    // point to BaseURL.
+    APIURL     string
+    Auth       Auth
+    HTTPClient *http.Client
+    // CAFile is a PEM bundle of certificate authorities to trust in
+    // addition to the system ones. CertFile and KeyFile are a PEM client
+    // certificate and key for servers that require mutual TLS. They are
+    // ignored when HTTPClient is set.
+    CAFile    string
+    CertFile  string
+    KeyFile   string
+    UserAgent string
+    // Timeout bounds every API call. Zero means DefaultTimeout.
+    Timeout time.Duration
+    // APIVersion selects the REST API version of requests that carry rich
+    // text, such as issues and comments: "2" uses wiki markup, "3" Atlassian
+    // Document Format. Empty means DefaultAPIVersion.
+    APIVersion string
+}
+
+type Client struct {
+    baseURL    *url.URL
+    apiURL     *url.URL
+    auth       Auth
+    httpClient *http.Client
+    userAgent  string
+    timeout    time.Duration
+    apiVersion string
+}
+
+// APIError is returned when Jira answers with a non-2xx status.
+type APIError struct {
+    StatusCode int
+    Messages   []string
+    Body       string
+}
+
+func (e *APIError) Error() string {
+    if len(e.Messages) > 0 {
+        return fmt.Sprintf("HTTP %d: %s", e.StatusCode, strings.Join(e.Messages, "; "))
+    }
+    if e.Body != "" {
+        return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+    }
+    return fmt.Sprintf("HTTP %d", e.StatusCode)
+}
+
+func NewClient(opts Options) (*Client, error) {
+    if opts.BaseURL == "" {
+        return nil, fmt.Errorf("jira base URL cannot be empty")
+    }
+
+    baseURL, err := url.Parse(NormalizeBaseURL(opts.BaseURL))
+    if err != nil {
+        return nil, fmt.Errorf("invalid jira base URL: %v", err)
+    }
+    if baseURL.Host == "" {
+        return nil, fmt.Errorf("invalid jira base URL: %s", opts.BaseURL)
+    }
+    apiURL := baseURL
+    if opts.APIURL != "" {
+        if apiURL, err = url.Parse(NormalizeBaseURL(opts.APIURL)); err != nil {
+            return nil, fmt.Errorf("invalid jira API URL: %v", err)
+        }
+    }
+
+    client := &Client{
+        baseURL:    baseURL,
+        apiURL:     apiURL,
+        auth:       opts.Auth,
+        httpClient: opts.HTTPClient,
+        userAgent:  opts.UserAgent,
+        timeout:    opts.Timeout,
+        apiVersion: opts.APIVersion,
+    }
+    if client.httpClient == nil {
+        transport, err := newTransport(opts.CAFile, opts.CertFile, opts.KeyFile)
+        if err != nil {
+            return nil, err
+        }
+        client.httpClient = &http.Client{Transport: transport}
+    }
+    if client.userAgent == "" {
+        client.userAgent = DefaultUserAgent
+    }
+    if client.timeout == 0 {
+        client.timeout = DefaultTimeout
+    }
+    if client.apiVersion == "" {
+        client.apiVersion = DefaultAPIVersion
+    }
+    if client.apiVersion != "2" && client.apiVersion != "3" {
+        return nil, fmt.Errorf("unsupported jira API version %q, expected 2 or 3", opts.APIVersion)
+    }
+
+    return client, nil
+}
+
+// NormalizeBaseURL turns a bare domain such as company.atlassian.net into
+// an https URL and strips trailing slashes. URLs with a scheme are kept as-is.
+func NormalizeBaseURL(domain string) string {
+    domain = strings.TrimSpace(domain)
+    if !strings.Contains(domain, "://") {
+        domain = "https://" + domain
+    }
+    return strings.TrimRight(domain, "/")
+}
+
+// BaseURL returns the site URL the client talks to.
+func (c *Client) BaseURL() string {
+    return c.baseURL.String()
+}
+
+// richTextIssuePath returns the path of an issue in the configured API
+// version, for requests whose rich text fields depend on it.
+func (c *Client) richTextIssuePath(issueKey string) string {
+    return "/rest/api/" + c.apiVersion + "/issue/" + url.PathEscape(issueKey)
+}
+
+// BrowseURL returns the web URL of an issue.
+func (c *Client) BrowseURL(issueKey string) string {
+    return c.BaseURL() + "/browse/" + issueKey
+}
+
+func (c *Client) endpoint(path string, query url.Values) string {
+    u := *c.apiURL
+    u.Path = strings.TrimRight(u.Path, "/") + path
+    u.RawPath = ""
+    if len(query) > 0 {
+        u.RawQuery = query.Encode()
+    }
+    return u.String()
+}
+
+// do sends a request to the REST API. body, when not nil, is sent as JSON
+// and out, when not nil, receives the decoded JSON response.
+func (c *Client) do(method, path string, query url.Values, body, out interface{}) error {
+    var reqBody io.Reader
+    if body != nil {
+        data, err := json.Marshal(body)
+        if err != nil {
+            return err
+        }
+        reqBody = bytes.NewReader(data)
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+    defer cancel()
+
+    req, err := http.NewRequestWithContext(ctx, method, c.endpoint(path, query), reqBody)
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Accept", "application/json")
+    req.Header.Set("User-Agent", c.userAgent)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    if c.auth != nil {
+        if err := c.auth.Apply(req); err != nil {
+            return err
+        }
+    }
+
+    resp, err := c.httpClient.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode < 200 || resp.StatusCode > 299 {
+        return newAPIError(resp)
+    }
+
+    if out == nil || resp.StatusCode == http.StatusNoContent {
+        return nil
+    }
+    return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// newTransport returns the default transport, or a copy trusting the extra
+// certificate authorities and presenting the client certificate.
+func newTransport(caFile, certFile, keyFile string) (http.RoundTripper, error) {
+    if caFile == "" && certFile == "" && keyFile == "" {
+        return http.DefaultTransport, nil
+    }
+
+    tlsConfig := &tls.Config{}
+    if caFile != "" {
+        pem, err := os.ReadFile(caFile)
+        if err != nil {
+            return nil, fmt.Errorf("failed to read CA bundle: %v", err)
+        }
+        pool, err := x509.SystemCertPool()
+        if err != nil || pool == nil {
+            pool = x509.NewCertPool()
+        }
+        if !pool.AppendCertsFromPEM(pem) {
+            return nil, fmt.Errorf("no certificates found in CA bundle %s", caFile)
+        }
+        tlsConfig.RootCAs = pool
+    }
+    if certFile != "" || keyFile != "" {
+        if certFile == "" || keyFile == "" {
+            return nil, fmt.Errorf("a client certificate needs both a certificate and a key file")
+        }
+        cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+        if err != nil {
+            return nil, fmt.Errorf("failed to load client certificate: %v", err)
+        }
+        tlsConfig.Certificates = []tls.Certificate{cert}
+    }
+
+    transport := http.DefaultTransport.(*http.Transport).Clone()
+    transport.TLSClientConfig = tlsConfig
+    return transport, nil
+}
+
+func newAPIError(resp *http.Response) error {
+    bodyBytes, _ := io.ReadAll(resp.Body)
+    apiErr := &APIError{
+        StatusCode: resp.StatusCode,
+        Body:       strings.TrimSpace(string(bodyBytes)),
+    }
+
+    var payload struct {
+        ErrorMessages []string          `json:"errorMessages"`
+        Errors        map[string]string `json:"errors"`
+    }
+    if json.Unmarshal(bodyBytes, &payload) == nil {
+        apiErr.Messages = append(apiErr.Messages, payload.ErrorMessages...)
+        fields := make([]string, 0, len(payload.Errors))
+        for field := range payload.Errors {
+            fields = append(fields, field)
+        }
+        sort.Strings(fields)
+        for _, field := range fields {
+            apiErr.Messages = append(apiErr.Messages, field+": "+payload.Errors[field])
+        }
+    }
+    return apiErr
+}
@@ -0,0 +1,252 @@
+package jira
+
+import (
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts Options) *Client {
+    t.Helper()
+    server := httptest.NewServer(handler)
+    t.Cleanup(server.Close)
+    opts.BaseURL = server.URL + opts.BaseURL
+    client, err := NewClient(opts)
+    if err != nil {
+        t.Fatalf("NewClient: %v", err)
+    }
+    return client
+}
+
+func TestClientHeaders(t *testing.T) {
+    tests := []struct {
+        name      string
+        opts      Options
+        auth      string
+        userAgent string
+    }{
+        {
+            name:      "basic auth and default user agent",
+            opts:      Options{Auth: BasicAuth{Email: "dev@example.com", APIToken: "secret"}},
+            auth:      "Basic ZGV2QGV4YW1wbGUuY29tOnNlY3JldA==",
+            userAgent: DefaultUserAgent,
+        },
+        {
+            name:      "bearer token and custom user agent",
+            opts:      Options{Auth: BearerAuth{Token: "pat"}, UserAgent: "jt-test/1.0"},
+            auth:      "Bearer pat",
+            userAgent: "jt-test/1.0",
+        },
+        {
+            name:      "no auth",
+            opts:      Options{},
+            userAgent: DefaultUserAgent,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var got *http.Request
+            client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+                got = r
+                w.Write([]byte(`{"accountId":"1","displayName":"Dev"}`))
+            }, tt.opts)
+
+            if _, err := client.Myself(); err != nil {
+                t.Fatalf("Myself: %v", err)
+            }
+            if auth := got.Header.Get("Authorization"); auth != tt.auth {
+                t.Errorf("Authorization = %q, want %q", auth, tt.auth)
+            }
+            if ua := got.Header.Get("User-Agent"); ua != tt.userAgent {
+                t.Errorf("User-Agent = %q, want %q", ua, tt.userAgent)
+            }
+            if accept := got.Header.Get("Accept"); accept != "application/json" {
+                t.Errorf("Accept = %q, want application/json", accept)
+            }
+        })
+    }
+}
+
+func TestClientAPIError(t *testing.T) {
+    tests := []struct {
+        name    string
+        status  int
+        body    string
+        message string
+    }{
+        {
+            name:    "error messages and field errors",
+            status:  http.StatusBadRequest,
+            body:    `{"errorMessages":["Issue does not exist"],"errors":{"summary":"required","assignee":"invalid"}}`,
+            message: "HTTP 400: Issue does not exist; assignee: invalid; summary: required",
+        },
+        {
+            name:    "plain text body",
+            status:  http.StatusBadGateway,
+            body:    "upstream down\n",
+            message: "HTTP 502: upstream down",
+        },
+        {
+            name:    "empty body",
+            status:  http.StatusUnauthorized,
+            message: "HTTP 401",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+                w.WriteHeader(tt.status)
+                w.Write([]byte(tt.body))
+            }, Options{})
+
+            _, err := client.FetchIssue("PROJ-1")
+            var apiErr *APIError
+            if !errors.As(err, &apiErr) {
+                t.Fatalf("FetchIssue error = %v, want *APIError", err)
+            }
+            if apiErr.StatusCode != tt.status {
+                t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+            }
+            if apiErr.Error() != tt.message {
+                t.Errorf("Error() = %q, want %q", apiErr.Error(), tt.message)
+            }
+        })
+    }
+}
+
+func TestClientTimeout(t *testing.T) {
+    release := make(chan struct{})
+    defer close(release)
+    client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+        select {
+        case <-release:
+        case <-r.Context().Done():
+        }
+    }, Options{Timeout: 50 * time.Millisecond})
+
+    start := time.Now()
+    _, err := client.Myself()
+    if err == nil {
+        t.Fatal("Myself succeeded, want a timeout error")
+    }
+    if elapsed := time.Since(start); elapsed > 5*time.Second {
+        t.Errorf("request took %v, the timeout was not applied", elapsed)
+    }
+}
+
+func TestNormalizeBaseURL(t *testing.T) {
+    tests := []struct {
+        in   string
+        want string
+    }{
+        {"company.atlassian.net", "https://company.atlassian.net"},
+        {" company.atlassian.net/ ", "https://company.atlassian.net"},
+        {"https://company.atlassian.net/", "https://company.atlassian.net"},
+        {"http://localhost:8080", "http://localhost:8080"},
+        {"jira.example.com/jira", "https://jira.example.com/jira"},
+        {"https://jira.example.com/jira//", "https://jira.example.com/jira"},
+    }
+    for _, tt := range tests {
+        if got := NormalizeBaseURL(tt.in); got != tt.want {
+            t.Errorf("NormalizeBaseURL(%q) = %q, want %q", tt.in, got, tt.want)
+        }
+    }
+}
+
+func TestClientContextPath(t *testing.T) {
+    var path string
+    client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+        path = r.URL.Path
+        w.Write([]byte(`{"key":"PROJ-1","fields":{"summary":"Login"}}`))
+    }, Options{BaseURL: "/jira/"})
+
+    if _, err := client.FetchIssue("PROJ-1"); err != nil {
+        t.Fatalf("FetchIssue: %v", err)
+    }
+    if path != "/jira/rest/api/2/issue/PROJ-1" {
+        t.Errorf("path = %q, want the context path kept", path)
+    }
+    if browse := client.BrowseURL("PROJ-1"); !strings.HasSuffix(browse, "/jira/browse/PROJ-1") {
+        t.Errorf("BrowseURL = %q, want the context path kept", browse)
+    }
+}
+
+func TestSearchPagination(t *testing.T) {
+    const total = 7
+    tests := []struct {
+        name     string
+        opts     SearchOptions
+        want     int
+        requests []searchRequest
+    }{
+        {
+            name: "all pages",
+            opts: SearchOptions{PageSize: 3},
+            want: total,
+            requests: []searchRequest{
+                {StartAt: 0, MaxResults: 3},
+                {StartAt: 3, MaxResults: 3},
+                {StartAt: 6, MaxResults: 3},
+            },
+        },
+        {
+            name: "limit within the second page",
+            opts: SearchOptions{PageSize: 3, Limit: 5},
+            want: 5,
+            requests: []searchRequest{
+                {StartAt: 0, MaxResults: 3},
+                {StartAt: 3, MaxResults: 2},
+            },
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var requests []searchRequest
+            client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+                if r.Method != http.MethodPost || r.URL.Path != "/rest/api/2/search" {
+                    t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+                }
+                var req searchRequest
+                if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+                    t.Fatalf("decoding search request: %v", err)
+                }
+                requests = append(requests, req)
+
+                page := searchResponse{StartAt: req.StartAt, MaxResults: req.MaxResults, Total: total}
+                for i := req.StartAt; i < total && i < req.StartAt+req.MaxResults; i++ {
+                    page.Issues = append(page.Issues, JiraIssue{Key: "PROJ-" + string(rune('1'+i))})
+                }
+                json.NewEncoder(w).Encode(page)
+            }, Options{})
+
+            issues, err := client.Search("project = PROJ", tt.opts)
+            if err != nil {
+                t.Fatalf("Search: %v", err)
+            }
+            if len(issues) != tt.want {
+                t.Errorf("got %d issues, want %d", len(issues), tt.want)
+            }
+            if len(requests) != len(tt.requests) {
+                t.Fatalf("sent %d requests, want %d", len(requests), len(tt.requests))
+            }
+            for i, req := range requests {
+                if req.JQL != "project = PROJ" {
+                    t.Errorf("request %d: JQL = %q", i, req.JQL)
+                }
+                if req.StartAt != tt.requests[i].StartAt || req.MaxResults != tt.requests[i].MaxResults {
+                    t.Errorf("request %d: startAt=%d maxResults=%d, want startAt=%d maxResults=%d",
+                        i, req.StartAt, req.MaxResults, tt.requests[i].StartAt, tt.requests[i].MaxResults)
+                }
+            }
+            for i, issue := range issues {
+                if want := "PROJ-" + string(rune('1'+i)); issue.Key != want {
+                    t.Errorf("issue %d = %s, want %s", i, issue.Key, want)
+                }
+            }
+        })
+    }
+}
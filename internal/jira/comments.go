@@ -0,0 +1,59 @@
+package jira
+
+import (
+    "net/http"
+    "net/url"
+    "strconv"
+    "time"
+)
+
+type Comment struct {
+    ID      string   `json:"id,omitempty"`
+    Author  *User    `json:"author,omitempty"`
+    Body    RichText `json:"body"`
+    Created string   `json:"created,omitempty"`
+    Updated string   `json:"updated,omitempty"`
+}
+
+// CreatedTime parses the comment's created timestamp.
+func (c *Comment) CreatedTime() (time.Time, error) {
+    return time.Parse(TimeLayout, c.Created)
+}
+
+// Comments returns every comment on an issue, oldest first.
+func (c *Client) Comments(issueKey string) ([]Comment, error) {
+    var comments []Comment
+    for {
+        query := url.Values{
+            "startAt":    {strconv.Itoa(len(comments))},
+            "maxResults": {"100"},
+            "orderBy":    {"created"},
+        }
+        var page struct {
+            Total    int       `json:"total"`
+            Comments []Comment `json:"comments"`
+        }
+        if err := c.do(http.MethodGet, c.richTextIssuePath(issueKey)+"/comment", query, nil, &page); err != nil {
+            return nil, err
+        }
+
+        comments = append(comments, page.Comments...)
+        if len(page.Comments) == 0 || len(comments) >= page.Total {
+            return comments, nil
+        }
+    }
+}
+
+// AddComment posts a comment on an issue. body is wiki markup for API
+// version 2 and an Atlassian Document Format document for version 3. It
+// returns the ID of the new comment.
+func (c *Client) AddComment(issueKey string, body interface{}) (string, error) {
+    path := c.richTextIssuePath(issueKey) + "/comment"
+    var created struct {
+        ID string `json:"id"`
+    }
+    if err := c.do(http.MethodPost, path, nil, map[string]interface{}{"body": body}, &created); err != nil {
+        return "", err
+    }
+    return created.ID, nil
+}
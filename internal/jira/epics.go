@@ -0,0 +1,66 @@
+package jira
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/url"
+)
+
+// epicLinkSchema identifies the Epic Link custom field of Jira Software on
+// Server and Data Center, whose field ID differs between instances.
+const epicLinkSchema = "com.pyxis.greenhopper.jira:gh-epic-link"
+
+// Field is a system or custom issue field.
+type Field struct {
+    ID     string `json:"id"`
+    Name   string `json:"name"`
+    Custom bool   `json:"custom"`
+    Schema struct {
+        Type   string `json:"type"`
+        Custom string `json:"custom"`
+    } `json:"schema"`
+}
+
+// Fields lists the issue fields of the instance.
+func (c *Client) Fields() ([]Field, error) {
+    var fields []Field
+    if err := c.do(http.MethodGet, "/rest/api/2/field", nil, nil, &fields); err != nil {
+        return nil, err
+    }
+    return fields, nil
+}
+
+// EpicLinkField returns the ID of the Epic Link field, e.g.
+// customfield_10008, or an empty string when the instance has none.
+func (c *Client) EpicLinkField() (string, error) {
+    fields, err := c.Fields()
+    if err != nil {
+        return "", err
+    }
+    for _, field := range fields {
+        if field.Schema.Custom == epicLinkSchema {
+            return field.ID, nil
+        }
+    }
+    return "", nil
+}
+
+// EpicLink returns the key of the epic an issue is linked to through the
+// Epic Link field, or an empty string.
+func (c *Client) EpicLink(issueKey, field string) (string, error) {
+    var issue struct {
+        Fields map[string]json.RawMessage `json:"fields"`
+    }
+    query := url.Values{"fields": {field}}
+    if err := c.do(http.MethodGet, issuePath(issueKey), query, nil, &issue); err != nil {
+        return "", err
+    }
+    var epicKey string
+    if raw, ok := issue.Fields[field]; ok {
+        // Unlinked issues have null, which leaves epicKey empty
+        if err := json.Unmarshal(raw, &epicKey); err != nil {
+            return "", err
+        }
+    }
+    return epicKey, nil
+}
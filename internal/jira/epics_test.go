@@ -0,0 +1,115 @@
+package jira
+
+import (
+    "encoding/json"
+    "net/http"
+    "testing"
+)
+
+func TestIssueEpic(t *testing.T) {
+    tests := []struct {
+        name   string
+        parent string
+        want   string
+    }{
+        {
+            name:   "epic parent",
+            parent: `{"key":"PROJ-1","fields":{"summary":"Login","issuetype":{"name":"Epic","hierarchyLevel":1}}}`,
+            want:   "PROJ-1",
+        },
+        {
+            name:   "translated epic type",
+            parent: `{"key":"PROJ-2","fields":{"summary":"Login","issuetype":{"name":"Épica","hierarchyLevel":1}}}`,
+            want:   "PROJ-2",
+        },
+        {
+            name:   "epic without hierarchy level",
+            parent: `{"key":"PROJ-3","fields":{"summary":"Login","issuetype":{"name":"Epic"}}}`,
+            want:   "PROJ-3",
+        },
+        {
+            name:   "parent of a sub-task",
+            parent: `{"key":"PROJ-4","fields":{"summary":"Login form","issuetype":{"name":"Story","hierarchyLevel":0}}}`,
+        },
+        {
+            name:   "no parent",
+            parent: `null`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var issue JiraIssue
+            if err := json.Unmarshal([]byte(`{"key":"PROJ-9","fields":{"parent":`+tt.parent+`}}`), &issue); err != nil {
+                t.Fatal(err)
+            }
+            got := ""
+            if epic := issue.Epic(); epic != nil {
+                got = epic.Key
+            }
+            if got != tt.want {
+                t.Errorf("Epic() = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestEpicLink(t *testing.T) {
+    client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/rest/api/2/field":
+            w.Write([]byte(`[
+                {"id":"summary","name":"Summary","custom":false,"schema":{"type":"string","system":"summary"}},
+                {"id":"customfield_10008","name":"Epic Link","custom":true,"schema":{"type":"any","custom":"com.pyxis.greenhopper.jira:gh-epic-link"}}
+            ]`))
+        case "/rest/api/2/issue/PROJ-1":
+            if fields := r.URL.Query().Get("fields"); fields != "customfield_10008" {
+                t.Errorf("fields = %q, want the Epic Link field only", fields)
+            }
+            w.Write([]byte(`{"key":"PROJ-1","fields":{"customfield_10008":"PROJ-100"}}`))
+        case "/rest/api/2/issue/PROJ-2":
+            w.Write([]byte(`{"key":"PROJ-2","fields":{"customfield_10008":null}}`))
+        default:
+            t.Errorf("unexpected request %s", r.URL.Path)
+            w.WriteHeader(http.StatusNotFound)
+        }
+    }, Options{})
+
+    field, err := client.EpicLinkField()
+    if err != nil {
+        t.Fatalf("EpicLinkField: %v", err)
+    }
+    if field != "customfield_10008" {
+        t.Fatalf("EpicLinkField = %q, want customfield_10008", field)
+    }
+
+    tests := []struct {
+        issueKey string
+        want     string
+    }{
+        {"PROJ-1", "PROJ-100"},
+        {"PROJ-2", ""},
+    }
+    for _, tt := range tests {
+        got, err := client.EpicLink(tt.issueKey, field)
+        if err != nil {
+            t.Fatalf("EpicLink(%s): %v", tt.issueKey, err)
+        }
+        if got != tt.want {
+            t.Errorf("EpicLink(%s) = %q, want %q", tt.issueKey, got, tt.want)
+        }
+    }
+}
+
+func TestEpicLinkFieldMissing(t *testing.T) {
+    client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(`[{"id":"parent","name":"Parent","custom":false,"schema":{"type":"issuelink","system":"parent"}}]`))
+    }, Options{})
+
+    field, err := client.EpicLinkField()
+    if err != nil {
+        t.Fatalf("EpicLinkField: %v", err)
+    }
+    if field != "" {
+        t.Errorf("EpicLinkField = %q, want none", field)
+    }
+}
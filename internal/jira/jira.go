@@ -1,87 +1,120 @@
 package jira
 
 import (
-    "encoding/base64"
-    "encoding/json"
     "fmt"
-    "io"
     "net/http"
-    "os"
+    "strings"
 )
 
 type JiraIssue struct {
     Key    string `json:"key"`
     Fields struct {
-        Summary     string `json:"summary"`
-        Description string `json:"description"`
+        Summary     string   `json:"summary"`
+        Description RichText `json:"description"`
         Status      struct {
             Name string `json:"name"`
         } `json:"status"`
-        Assignee struct {
-            DisplayName string `json:"displayName"`
-        } `json:"assignee"`
+        Assignee  User `json:"assignee"`
+        Reporter  User `json:"reporter"`
+        IssueType struct {
+            Name string `json:"name"`
+        } `json:"issuetype"`
+        Priority struct {
+            Name string `json:"name"`
+        } `json:"priority"`
+        Project struct {
+            Key  string `json:"key"`
+            Name string `json:"name"`
+        } `json:"project"`
+        Components []struct {
+            Name string `json:"name"`
+        } `json:"components"`
+        Labels  []string     `json:"labels"`
+        Parent  *ParentIssue `json:"parent,omitempty"`
+        Created string       `json:"created"`
+        Updated string       `json:"updated"`
     } `json:"fields"`
 }
 
-func ValidateCredentials(domain, email, apiToken string) error {
-    url := fmt.Sprintf("https://%s/rest/api/2/myself", domain)
-
-    req, err := http.NewRequest("GET", url, nil)
-    if err != nil {
-        return err
-    }
-
-    auth := email + ":" + apiToken
-    encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
-    req.Header.Add("Authorization", "Basic "+encodedAuth)
+// ParentIssue is the parent of a sub-task, or the epic of an issue in
+// projects that link epics through the parent field.
+type ParentIssue struct {
+    Key    string `json:"key"`
+    Fields struct {
+        Summary   string `json:"summary"`
+        IssueType struct {
+            Name string `json:"name"`
+            // HierarchyLevel is 1 for epics, 0 for standard issues and -1
+            // for sub-tasks. Only Jira Cloud reports it.
+            HierarchyLevel int `json:"hierarchyLevel"`
+        } `json:"issuetype"`
+    } `json:"fields"`
+}
 
-    client := &http.Client{}
-    resp, err := client.Do(req)
-    if err != nil {
-        return err
+// Epic returns the epic an issue belongs to through its parent field, or
+// nil. Epics are recognised by their hierarchy level, so renamed and
+// translated epic types count too. Server and Data Center link epics through
+// the Epic Link field instead, see EpicLink.
+func (i *JiraIssue) Epic() *ParentIssue {
+    parent := i.Fields.Parent
+    if parent == nil {
+        return nil
     }
-    defer resp.Body.Close()
-
-    if resp.StatusCode != http.StatusOK {
-        return fmt.Errorf("invalid credentials (HTTP %d)", resp.StatusCode)
+    if parent.Fields.IssueType.HierarchyLevel == 1 || parent.Fields.IssueType.Name == "Epic" {
+        return parent
     }
-
     return nil
 }
 
-func FetchIssue(issueKey string) (*JiraIssue, error) {
-    domain := os.Getenv("JIRA_DOMAIN")
-    email := os.Getenv("JIRA_EMAIL")
-    apiToken := os.Getenv("JIRA_API_TOKEN")
-
-    url := fmt.Sprintf("https://%s/rest/api/2/issue/%s", domain, issueKey)
-
-    req, err := http.NewRequest("GET", url, nil)
-    if err != nil {
-        return nil, err
+// ProjectKey returns the project key of an issue key, e.g. PROJ for
+// PROJ-123.
+func ProjectKey(issueKey string) string {
+    if i := strings.LastIndex(issueKey, "-"); i > 0 {
+        return issueKey[:i]
     }
+    return issueKey
+}
 
-    auth := email + ":" + apiToken
-    encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
-    req.Header.Add("Authorization", "Basic "+encodedAuth)
-    req.Header.Add("Accept", "application/json")
+// User is a Jira account. Cloud identifies users by AccountID, Server and
+// Data Center by Name.
+type User struct {
+    AccountID    string `json:"accountId,omitempty"`
+    Name         string `json:"name,omitempty"`
+    EmailAddress string `json:"emailAddress,omitempty"`
+    DisplayName  string `json:"displayName,omitempty"`
+}
 
-    client := &http.Client{}
-    resp, err := client.Do(req)
-    if err != nil {
+func (c *Client) Myself() (*User, error) {
+    var user User
+    if err := c.do(http.MethodGet, "/rest/api/2/myself", nil, nil, &user); err != nil {
         return nil, err
     }
-    defer resp.Body.Close()
+    return &user, nil
+}
 
-    if resp.StatusCode != http.StatusOK {
-        bodyBytes, _ := io.ReadAll(resp.Body)
-        return nil, fmt.Errorf("failed to fetch issue: %s", string(bodyBytes))
+func (c *Client) ValidateCredentials() error {
+    if _, err := c.Myself(); err != nil {
+        if apiErr, ok := err.(*APIError); ok {
+            return fmt.Errorf("invalid credentials (HTTP %d)", apiErr.StatusCode)
+        }
+        return err
     }
+    return nil
+}
 
+func (c *Client) FetchIssue(issueKey string) (*JiraIssue, error) {
     var issue JiraIssue
-    if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
-        return nil, err
+    if err := c.do(http.MethodGet, c.richTextIssuePath(issueKey), nil, nil, &issue); err != nil {
+        return nil, fmt.Errorf("failed to fetch issue: %w", err)
     }
-
     return &issue, nil
 }
+
+// AssignIssue sets the assignee of an issue.
+func (c *Client) AssignIssue(issueKey string, user *User) error {
+    body := map[string]string{"accountId": user.AccountID}
+    if user.AccountID == "" {
+        body = map[string]string{"name": user.Name}
+    }
+    return c.do(http.MethodPut, issuePath(issueKey)+"/assignee", nil, body, nil)
+}
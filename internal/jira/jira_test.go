@@ -0,0 +1,21 @@
+package jira
+
+import "testing"
+
+func TestProjectKey(t *testing.T) {
+    tests := []struct {
+        in   string
+        want string
+    }{
+        {"PROJ-123", "PROJ"},
+        {"MY_PROJ-1", "MY_PROJ"},
+        {"A2-B-7", "A2-B"},
+        {"PROJ", "PROJ"},
+        {"-1", "-1"},
+    }
+    for _, tt := range tests {
+        if got := ProjectKey(tt.in); got != tt.want {
+            t.Errorf("ProjectKey(%q) = %q, want %q", tt.in, got, tt.want)
+        }
+    }
+}
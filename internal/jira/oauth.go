@@ -0,0 +1,224 @@
+package jira
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "strings"
+    "sync"
+    "time"
+)
+
+// Atlassian OAuth 2.0 (3LO) endpoints.
+const (
+    OAuthAuthorizeURL = "https://auth.atlassian.com/authorize"
+    OAuthTokenURL     = "https://auth.atlassian.com/oauth/token"
+    OAuthResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+)
+
+// OAuthScopes are the scopes jt asks for. offline_access grants the
+// refresh token.
+var OAuthScopes = []string{"read:jira-work", "write:jira-work", "read:jira-user", "offline_access"}
+
+// OAuthConfig identifies an OAuth 2.0 app registered in the Atlassian
+// developer console.
+type OAuthConfig struct {
+    ClientID     string
+    ClientSecret string
+    // RedirectURL is the callback URL registered for the app.
+    RedirectURL string
+    HTTPClient  *http.Client
+    // TokenURL and ResourcesURL override the Atlassian endpoints.
+    TokenURL     string
+    ResourcesURL string
+}
+
+// OAuthToken is an access token with the refresh token to renew it.
+type OAuthToken struct {
+    AccessToken  string    `json:"access_token"`
+    RefreshToken string    `json:"refresh_token,omitempty"`
+    Expiry       time.Time `json:"expiry"`
+    // CloudID identifies the Jira site the token is used for.
+    CloudID string `json:"cloud_id,omitempty"`
+}
+
+// Expired reports whether the token expires within the next minute.
+func (t *OAuthToken) Expired() bool {
+    return !t.Expiry.IsZero() && time.Now().Add(time.Minute).After(t.Expiry)
+}
+
+// CloudResource is a site the user granted the app access to.
+type CloudResource struct {
+    ID     string   `json:"id"`
+    URL    string   `json:"url"`
+    Name   string   `json:"name"`
+    Scopes []string `json:"scopes"`
+}
+
+// OAuthAPIURL returns the API gateway URL of a site, for Options.APIURL.
+func OAuthAPIURL(cloudID string) string {
+    return "https://api.atlassian.com/ex/jira/" + cloudID
+}
+
+// AuthCodeURL returns the URL where the user grants access. state is sent
+// back to the redirect URL and must be checked there.
+func (c OAuthConfig) AuthCodeURL(state string) string {
+    query := url.Values{
+        "audience":      {"api.atlassian.com"},
+        "client_id":     {c.ClientID},
+        "scope":         {strings.Join(OAuthScopes, " ")},
+        "redirect_uri":  {c.RedirectURL},
+        "state":         {state},
+        "response_type": {"code"},
+        "prompt":        {"consent"},
+    }
+    return OAuthAuthorizeURL + "?" + query.Encode()
+}
+
+// Exchange trades the authorization code received on the redirect URL for
+// a token.
+func (c OAuthConfig) Exchange(code string) (*OAuthToken, error) {
+    return c.requestToken(map[string]string{
+        "grant_type":    "authorization_code",
+        "client_id":     c.ClientID,
+        "client_secret": c.ClientSecret,
+        "code":          code,
+        "redirect_uri":  c.RedirectURL,
+    })
+}
+
+// Refresh renews a token. Atlassian rotates refresh tokens, so the
+// returned token replaces the old one entirely.
+func (c OAuthConfig) Refresh(token *OAuthToken) (*OAuthToken, error) {
+    if token.RefreshToken == "" {
+        return nil, fmt.Errorf("the OAuth token expired and has no refresh token, log in again")
+    }
+    refreshed, err := c.requestToken(map[string]string{
+        "grant_type":    "refresh_token",
+        "client_id":     c.ClientID,
+        "client_secret": c.ClientSecret,
+        "refresh_token": token.RefreshToken,
+    })
+    if err != nil {
+        return nil, err
+    }
+    if refreshed.RefreshToken == "" {
+        refreshed.RefreshToken = token.RefreshToken
+    }
+    refreshed.CloudID = token.CloudID
+    return refreshed, nil
+}
+
+func (c OAuthConfig) requestToken(body map[string]string) (*OAuthToken, error) {
+    tokenURL := c.TokenURL
+    if tokenURL == "" {
+        tokenURL = OAuthTokenURL
+    }
+    var resp struct {
+        AccessToken  string `json:"access_token"`
+        RefreshToken string `json:"refresh_token"`
+        ExpiresIn    int    `json:"expires_in"`
+    }
+    if err := c.send(http.MethodPost, tokenURL, "", body, &resp); err != nil {
+        return nil, fmt.Errorf("failed to get OAuth token: %w", err)
+    }
+
+    token := &OAuthToken{AccessToken: resp.AccessToken, RefreshToken: resp.RefreshToken}
+    if resp.ExpiresIn > 0 {
+        token.Expiry = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+    }
+    return token, nil
+}
+
+// AccessibleResources lists the sites a token grants access to.
+func (c OAuthConfig) AccessibleResources(token *OAuthToken) ([]CloudResource, error) {
+    resourcesURL := c.ResourcesURL
+    if resourcesURL == "" {
+        resourcesURL = OAuthResourcesURL
+    }
+    var resources []CloudResource
+    if err := c.send(http.MethodGet, resourcesURL, token.AccessToken, nil, &resources); err != nil {
+        return nil, fmt.Errorf("failed to list accessible sites: %w", err)
+    }
+    return resources, nil
+}
+
+func (c OAuthConfig) send(method, rawURL, accessToken string, body, out interface{}) error {
+    var reqBody io.Reader
+    if body != nil {
+        data, err := json.Marshal(body)
+        if err != nil {
+            return err
+        }
+        reqBody = bytes.NewReader(data)
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+    defer cancel()
+
+    req, err := http.NewRequestWithContext(ctx, method, rawURL, reqBody)
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Accept", "application/json")
+    req.Header.Set("User-Agent", DefaultUserAgent)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    if accessToken != "" {
+        req.Header.Set("Authorization", "Bearer "+accessToken)
+    }
+
+    httpClient := c.HTTPClient
+    if httpClient == nil {
+        httpClient = http.DefaultClient
+    }
+    resp, err := httpClient.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode < 200 || resp.StatusCode > 299 {
+        return newAPIError(resp)
+    }
+    return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// OAuth2Auth authenticates with an OAuth 2.0 (3LO) access token, refreshing
+// it when it expires.
+type OAuth2Auth struct {
+    Config OAuthConfig
+    Token  *OAuthToken
+    // OnRefresh is called with every refreshed token so it can be saved.
+    // The refreshed token is used whether or not saving works: the rotation
+    // has already invalidated the old refresh token.
+    OnRefresh func(*OAuthToken)
+
+    mu sync.Mutex
+}
+
+func (a *OAuth2Auth) Apply(req *http.Request) error {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+
+    if a.Token == nil {
+        return fmt.Errorf("not logged in with OAuth")
+    }
+    if a.Token.Expired() {
+        token, err := a.Config.Refresh(a.Token)
+        if err != nil {
+            return err
+        }
+        a.Token = token
+        if a.OnRefresh != nil {
+            a.OnRefresh(token)
+        }
+    }
+    req.Header.Set("Authorization", "Bearer "+a.Token.AccessToken)
+    return nil
+}
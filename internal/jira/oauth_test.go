@@ -0,0 +1,182 @@
+package jira
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+// newTokenServer serves the OAuth token endpoint, recording the request
+// bodies it receives.
+func newTokenServer(t *testing.T, status int, response string) (OAuthConfig, *[]map[string]string) {
+    t.Helper()
+    var requests []map[string]string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost || r.URL.Path != "/oauth/token" {
+            t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+        }
+        if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+            t.Errorf("Content-Type = %q, want application/json", ct)
+        }
+        var body map[string]string
+        if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+            t.Errorf("decoding token request: %v", err)
+        }
+        requests = append(requests, body)
+        w.WriteHeader(status)
+        w.Write([]byte(response))
+    }))
+    t.Cleanup(server.Close)
+    return OAuthConfig{
+        ClientID:     "client",
+        ClientSecret: "secret",
+        RedirectURL:  "http://localhost:8085/callback",
+        TokenURL:     server.URL + "/oauth/token",
+    }, &requests
+}
+
+func TestOAuthRefresh(t *testing.T) {
+    tests := []struct {
+        name     string
+        response string
+        refresh  string
+    }{
+        {
+            name:     "rotated refresh token",
+            response: `{"access_token":"new-access","refresh_token":"new-refresh","expires_in":3600}`,
+            refresh:  "new-refresh",
+        },
+        {
+            name:     "refresh token kept",
+            response: `{"access_token":"new-access","expires_in":3600}`,
+            refresh:  "old-refresh",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            cfg, requests := newTokenServer(t, http.StatusOK, tt.response)
+            old := &OAuthToken{AccessToken: "old-access", RefreshToken: "old-refresh", CloudID: "cloud-1"}
+
+            token, err := cfg.Refresh(old)
+            if err != nil {
+                t.Fatalf("Refresh: %v", err)
+            }
+            want := map[string]string{
+                "grant_type":    "refresh_token",
+                "client_id":     "client",
+                "client_secret": "secret",
+                "refresh_token": "old-refresh",
+            }
+            if len(*requests) != 1 || !equalMaps((*requests)[0], want) {
+                t.Errorf("token requests = %v, want %v", *requests, want)
+            }
+            if token.AccessToken != "new-access" || token.RefreshToken != tt.refresh || token.CloudID != "cloud-1" {
+                t.Errorf("token = %+v, want new-access, %s and cloud-1", token, tt.refresh)
+            }
+            if remaining := time.Until(token.Expiry); remaining < 59*time.Minute || remaining > time.Hour {
+                t.Errorf("token expires in %v, want about an hour", remaining)
+            }
+        })
+    }
+}
+
+func TestOAuthRefreshErrors(t *testing.T) {
+    cfg, requests := newTokenServer(t, http.StatusForbidden, `{"error":"invalid_grant","error_description":"Unknown or invalid refresh token."}`)
+
+    if _, err := cfg.Refresh(&OAuthToken{AccessToken: "old"}); err == nil || !strings.Contains(err.Error(), "no refresh token") {
+        t.Errorf("Refresh without a refresh token error = %v", err)
+    }
+    if len(*requests) != 0 {
+        t.Errorf("sent %d token requests without a refresh token", len(*requests))
+    }
+
+    _, err := cfg.Refresh(&OAuthToken{RefreshToken: "revoked"})
+    if err == nil || !strings.Contains(err.Error(), "failed to get OAuth token") || !strings.Contains(err.Error(), "HTTP 403") {
+        t.Errorf("Refresh with a revoked token error = %v", err)
+    }
+}
+
+func TestOAuthExchange(t *testing.T) {
+    cfg, requests := newTokenServer(t, http.StatusOK, `{"access_token":"access","refresh_token":"refresh","expires_in":3600}`)
+
+    token, err := cfg.Exchange("code-1")
+    if err != nil {
+        t.Fatalf("Exchange: %v", err)
+    }
+    want := map[string]string{
+        "grant_type":    "authorization_code",
+        "client_id":     "client",
+        "client_secret": "secret",
+        "code":          "code-1",
+        "redirect_uri":  "http://localhost:8085/callback",
+    }
+    if len(*requests) != 1 || !equalMaps((*requests)[0], want) {
+        t.Errorf("token requests = %v, want %v", *requests, want)
+    }
+    if token.AccessToken != "access" || token.RefreshToken != "refresh" {
+        t.Errorf("token = %+v", token)
+    }
+}
+
+func TestOAuthAccessibleResources(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if auth := r.Header.Get("Authorization"); auth != "Bearer access" {
+            t.Errorf("Authorization = %q, want the access token", auth)
+        }
+        w.Write([]byte(`[{"id":"cloud-1","url":"https://acme.atlassian.net","name":"acme","scopes":["read:jira-work"]}]`))
+    }))
+    defer server.Close()
+
+    cfg := OAuthConfig{ResourcesURL: server.URL}
+    resources, err := cfg.AccessibleResources(&OAuthToken{AccessToken: "access"})
+    if err != nil {
+        t.Fatalf("AccessibleResources: %v", err)
+    }
+    if len(resources) != 1 || resources[0].ID != "cloud-1" || resources[0].URL != "https://acme.atlassian.net" {
+        t.Errorf("resources = %+v", resources)
+    }
+}
+
+func TestOAuth2AuthApply(t *testing.T) {
+    cfg, requests := newTokenServer(t, http.StatusOK, `{"access_token":"new-access","refresh_token":"new-refresh","expires_in":3600}`)
+    var saved []*OAuthToken
+    auth := &OAuth2Auth{
+        Config:    cfg,
+        Token:     &OAuthToken{AccessToken: "old-access", RefreshToken: "old-refresh", Expiry: time.Now().Add(-time.Minute)},
+        OnRefresh: func(token *OAuthToken) { saved = append(saved, token) },
+    }
+
+    for i := 0; i < 2; i++ {
+        req := httptest.NewRequest(http.MethodGet, "/rest/api/2/myself", nil)
+        if err := auth.Apply(req); err != nil {
+            t.Fatalf("Apply: %v", err)
+        }
+        if got := req.Header.Get("Authorization"); got != "Bearer new-access" {
+            t.Errorf("request %d: Authorization = %q, want the refreshed token", i, got)
+        }
+    }
+    if len(*requests) != 1 {
+        t.Errorf("refreshed %d times, want once", len(*requests))
+    }
+    if len(saved) != 1 || saved[0].RefreshToken != "new-refresh" {
+        t.Errorf("OnRefresh got %v, want the rotated token once", saved)
+    }
+    if auth.Token.RefreshToken != "new-refresh" {
+        t.Errorf("kept refresh token %q, want the rotated one", auth.Token.RefreshToken)
+    }
+}
+
+func equalMaps(a, b map[string]string) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for key, value := range a {
+        if b[key] != value {
+            return false
+        }
+    }
+    return true
+}
@@ -0,0 +1,31 @@
+package jira
+
+import "net/http"
+
+// RemoteLink links an issue to a web page such as a branch or pull request.
+type RemoteLink struct {
+    // GlobalID identifies the link: posting a link with the same GlobalID
+    // again updates it instead of adding a duplicate.
+    GlobalID     string                 `json:"globalId,omitempty"`
+    Application  *RemoteLinkApplication `json:"application,omitempty"`
+    Relationship string                 `json:"relationship,omitempty"`
+    Object       RemoteLinkObject       `json:"object"`
+}
+
+// RemoteLinkApplication groups links by the application they point to.
+type RemoteLinkApplication struct {
+    Type string `json:"type,omitempty"`
+    Name string `json:"name,omitempty"`
+}
+
+type RemoteLinkObject struct {
+    URL     string `json:"url"`
+    Title   string `json:"title"`
+    Summary string `json:"summary,omitempty"`
+}
+
+// CreateRemoteLink adds a remote link to an issue, or updates the link
+// with the same GlobalID.
+func (c *Client) CreateRemoteLink(issueKey string, link RemoteLink) error {
+    return c.do(http.MethodPost, issuePath(issueKey)+"/remotelink", nil, link, nil)
+}
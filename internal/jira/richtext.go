@@ -0,0 +1,68 @@
+package jira
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+
+    "jira-tools/internal/adf"
+)
+
+// RichText is a rich text field such as an issue description or comment
+// body. Version 2 of the API returns wiki markup as a string, version 3 an
+// Atlassian Document Format document; RichText decodes either.
+type RichText struct {
+    Wiki string
+    Doc  *adf.Node
+}
+
+func (t *RichText) UnmarshalJSON(data []byte) error {
+    data = bytes.TrimSpace(data)
+    switch {
+    case bytes.Equal(data, []byte("null")):
+        *t = RichText{}
+        return nil
+    case len(data) > 0 && data[0] == '"':
+        *t = RichText{}
+        return json.Unmarshal(data, &t.Wiki)
+    case len(data) > 0 && data[0] == '{':
+        var doc adf.Node
+        if err := json.Unmarshal(data, &doc); err != nil {
+            return err
+        }
+        *t = RichText{Doc: &doc}
+        return nil
+    default:
+        return fmt.Errorf("rich text must be a string or a document, got %s", data)
+    }
+}
+
+func (t RichText) MarshalJSON() ([]byte, error) {
+    if t.Doc != nil {
+        return json.Marshal(t.Doc)
+    }
+    return json.Marshal(t.Wiki)
+}
+
+// IsEmpty reports whether the field has no content.
+func (t RichText) IsEmpty() bool {
+    return t.Wiki == "" && (t.Doc == nil || len(t.Doc.Content) == 0)
+}
+
+// Terminal renders the field for display in the terminal. Wiki markup is
+// returned as-is.
+func (t RichText) Terminal() string {
+    if t.Doc != nil {
+        return adf.Terminal(t.Doc)
+    }
+    return t.Wiki
+}
+
+// Markdown renders the field as Markdown, for pull requests and commit
+// messages. Wiki markup is returned as-is.
+func (t RichText) Markdown() string {
+    if t.Doc != nil {
+        return adf.Markdown(t.Doc)
+    }
+    return t.Wiki
+}
@@ -0,0 +1,44 @@
+package jira
+
+import (
+    "encoding/json"
+    "testing"
+)
+
+func TestRichText(t *testing.T) {
+    tests := []struct {
+        name     string
+        data     string
+        empty    bool
+        terminal string
+    }{
+        {name: "null", data: `null`, empty: true},
+        {name: "empty wiki", data: `""`, empty: true},
+        {name: "wiki", data: `"h1. Title"`, terminal: "h1. Title"},
+        {name: "empty document", data: `{"type":"doc","version":1,"content":[]}`, empty: true},
+        {
+            name:     "document",
+            data:     `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"Hello"}]}]}`,
+            terminal: "Hello",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var text RichText
+            if err := json.Unmarshal([]byte(tt.data), &text); err != nil {
+                t.Fatalf("Unmarshal: %v", err)
+            }
+            if text.IsEmpty() != tt.empty {
+                t.Errorf("IsEmpty() = %v, want %v", text.IsEmpty(), tt.empty)
+            }
+            if got := text.Terminal(); got != tt.terminal {
+                t.Errorf("Terminal() = %q, want %q", got, tt.terminal)
+            }
+        })
+    }
+
+    var text RichText
+    if err := json.Unmarshal([]byte(`42`), &text); err == nil {
+        t.Error("Unmarshal accepted a number")
+    }
+}
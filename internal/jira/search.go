@@ -0,0 +1,71 @@
+package jira
+
+import (
+    "net/http"
+)
+
+// DefaultPageSize is the number of issues requested per search page.
+const DefaultPageSize = 50
+
+type SearchOptions struct {
+    // Fields limits the issue fields returned by Jira. Empty means Jira's
+    // navigable defaults.
+    Fields []string
+    // PageSize is the maxResults sent with every page request.
+    PageSize int
+    // Limit caps the total number of issues returned. Zero means no cap.
+    Limit int
+}
+
+type searchRequest struct {
+    JQL        string   `json:"jql"`
+    StartAt    int      `json:"startAt"`
+    MaxResults int      `json:"maxResults"`
+    Fields     []string `json:"fields,omitempty"`
+}
+
+type searchResponse struct {
+    StartAt    int         `json:"startAt"`
+    MaxResults int         `json:"maxResults"`
+    Total      int         `json:"total"`
+    Issues     []JiraIssue `json:"issues"`
+}
+
+// Search runs a JQL query, following startAt/maxResults pagination until
+// every result has been fetched or opts.Limit is reached.
+func (c *Client) Search(jql string, opts SearchOptions) ([]JiraIssue, error) {
+    pageSize := opts.PageSize
+    if pageSize <= 0 {
+        pageSize = DefaultPageSize
+    }
+
+    var issues []JiraIssue
+    for {
+        maxResults := pageSize
+        if opts.Limit > 0 && opts.Limit-len(issues) < maxResults {
+            maxResults = opts.Limit - len(issues)
+        }
+
+        req := searchRequest{
+            JQL:        jql,
+            StartAt:    len(issues),
+            MaxResults: maxResults,
+            Fields:     opts.Fields,
+        }
+        var page searchResponse
+        if err := c.do(http.MethodPost, "/rest/api/2/search", nil, req, &page); err != nil {
+            return nil, err
+        }
+
+        issues = append(issues, page.Issues...)
+
+        if len(page.Issues) == 0 || len(issues) >= page.Total {
+            break
+        }
+        if opts.Limit > 0 && len(issues) >= opts.Limit {
+            break
+        }
+    }
+
+    return issues, nil
+}
@@ -0,0 +1,100 @@
+package jira
+
+import (
+    "net/http"
+    "net/url"
+    "strings"
+)
+
+type Transition struct {
+    ID   string `json:"id"`
+    Name string `json:"name"`
+    To   struct {
+        Name           string `json:"name"`
+        StatusCategory struct {
+            Key  string `json:"key"`
+            Name string `json:"name"`
+        } `json:"statusCategory"`
+    } `json:"to"`
+    Fields map[string]TransitionField `json:"fields"`
+}
+
+// TransitionField is a field shown on a transition screen.
+type TransitionField struct {
+    Required        bool           `json:"required"`
+    Name            string         `json:"name"`
+    HasDefaultValue bool           `json:"hasDefaultValue"`
+    AllowedValues   []AllowedValue `json:"allowedValues"`
+    Schema          struct {
+        Type   string `json:"type"`
+        System string `json:"system"`
+    } `json:"schema"`
+}
+
+type AllowedValue struct {
+    ID    string `json:"id"`
+    Name  string `json:"name,omitempty"`
+    Value string `json:"value,omitempty"`
+}
+
+// Label returns the human readable name of an allowed value.
+func (v AllowedValue) Label() string {
+    if v.Name != "" {
+        return v.Name
+    }
+    return v.Value
+}
+
+func issuePath(issueKey string) string {
+    return "/rest/api/2/issue/" + url.PathEscape(issueKey)
+}
+
+// Transitions lists the workflow transitions currently available on an
+// issue, including the fields each transition screen requires.
+func (c *Client) Transitions(issueKey string) ([]Transition, error) {
+    query := url.Values{"expand": {"transitions.fields"}}
+    var resp struct {
+        Transitions []Transition `json:"transitions"`
+    }
+    if err := c.do(http.MethodGet, issuePath(issueKey)+"/transitions", query, nil, &resp); err != nil {
+        return nil, err
+    }
+    return resp.Transitions, nil
+}
+
+// DoTransition moves an issue through a transition. fields holds values for
+// fields on the transition screen, e.g. {"resolution": {"name": "Done"}}.
+func (c *Client) DoTransition(issueKey, transitionID string, fields map[string]interface{}) error {
+    body := map[string]interface{}{
+        "transition": map[string]string{"id": transitionID},
+    }
+    if len(fields) > 0 {
+        body["fields"] = fields
+    }
+    return c.do(http.MethodPost, issuePath(issueKey)+"/transitions", nil, body, nil)
+}
+
+// MatchTransitions finds the transitions leading to target. Transitions whose
+// target status or name equals target (case-insensitively) win and exact is
+// set; otherwise every transition containing target is returned, and the
+// caller should ask before using one, as "done" also matches "Not Done".
+func MatchTransitions(transitions []Transition, target string) (matches []Transition, exact bool) {
+    target = strings.ToLower(strings.TrimSpace(target))
+
+    var exactMatches, partial []Transition
+    for _, t := range transitions {
+        to := strings.ToLower(t.To.Name)
+        name := strings.ToLower(t.Name)
+        switch {
+        case to == target || name == target:
+            exactMatches = append(exactMatches, t)
+        case strings.Contains(to, target) || strings.Contains(name, target):
+            partial = append(partial, t)
+        }
+    }
+
+    if len(exactMatches) > 0 {
+        return exactMatches, true
+    }
+    return partial, false
+}
@@ -0,0 +1,175 @@
+package jira
+
+import (
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/tls"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/pem"
+    "io"
+    "log"
+    "math/big"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+)
+
+type testCert struct {
+    cert *x509.Certificate
+    key  *ecdsa.PrivateKey
+    der  []byte
+}
+
+// newTestCert creates a certificate signed by parent, or a self-signed
+// certificate authority when parent is nil.
+func newTestCert(t *testing.T, name string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+    t.Helper()
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    template := &x509.Certificate{
+        SerialNumber: big.NewInt(time.Now().UnixNano()),
+        Subject:      pkix.Name{CommonName: name},
+        NotBefore:    time.Now().Add(-time.Hour),
+        NotAfter:     time.Now().Add(time.Hour),
+        KeyUsage:     x509.KeyUsageDigitalSignature,
+    }
+    signer, signerKey := template, key
+    if parent == nil {
+        template.IsCA = true
+        template.BasicConstraintsValid = true
+        template.KeyUsage |= x509.KeyUsageCertSign
+    } else {
+        template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+        template.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1)}
+        signer, signerKey = parent.cert, parent.key
+    }
+    der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+    cert, err := x509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return &testCert{cert: cert, key: key, der: der}
+}
+
+// writePEM writes the certificate, and its key when keyPath is set.
+func (c *testCert) writePEM(t *testing.T, certPath, keyPath string) {
+    t.Helper()
+    writeTestFile(t, certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}))
+    if keyPath == "" {
+        return
+    }
+    der, err := x509.MarshalECPrivateKey(c.key)
+    if err != nil {
+        t.Fatal(err)
+    }
+    writeTestFile(t, keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+}
+
+func writeTestFile(t *testing.T, path string, data []byte) {
+    t.Helper()
+    if err := os.WriteFile(path, data, 0600); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestClientTLS(t *testing.T) {
+    ca := newTestCert(t, "Test CA", nil, 0)
+    serverCert := newTestCert(t, "jira.test", ca, x509.ExtKeyUsageServerAuth)
+    clientCert := newTestCert(t, "dev", ca, x509.ExtKeyUsageClientAuth)
+
+    dir := t.TempDir()
+    caFile := filepath.Join(dir, "ca.pem")
+    certFile := filepath.Join(dir, "client.pem")
+    keyFile := filepath.Join(dir, "client-key.pem")
+    ca.writePEM(t, caFile, "")
+    clientCert.writePEM(t, certFile, keyFile)
+    notPEM := filepath.Join(dir, "not-pem.txt")
+    writeTestFile(t, notPEM, []byte("not a certificate\n"))
+
+    pool := x509.NewCertPool()
+    pool.AddCert(ca.cert)
+    server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(`{"accountId":"1","displayName":"Dev"}`))
+    }))
+    server.TLS = &tls.Config{
+        Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.der}, PrivateKey: serverCert.key}},
+        ClientCAs:    pool,
+        ClientAuth:   tls.RequireAndVerifyClientCert,
+    }
+    // The failing handshakes are expected
+    server.Config.ErrorLog = log.New(io.Discard, "", 0)
+    server.StartTLS()
+    defer server.Close()
+
+    tests := []struct {
+        name   string
+        opts   Options
+        errMsg string
+    }{
+        {
+            name: "CA file and client certificate",
+            opts: Options{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+        },
+        {
+            name:   "no client certificate",
+            opts:   Options{CAFile: caFile},
+            errMsg: "tls",
+        },
+        {
+            name:   "server CA not trusted",
+            opts:   Options{CertFile: certFile, KeyFile: keyFile},
+            errMsg: "certificate",
+        },
+        {
+            name:   "certificate without a key",
+            opts:   Options{CAFile: caFile, CertFile: certFile},
+            errMsg: "needs both a certificate and a key file",
+        },
+        {
+            name:   "missing CA file",
+            opts:   Options{CAFile: filepath.Join(dir, "missing.pem")},
+            errMsg: "failed to read CA bundle",
+        },
+        {
+            name:   "CA file without certificates",
+            opts:   Options{CAFile: notPEM},
+            errMsg: "no certificates found in CA bundle",
+        },
+        {
+            name:   "invalid client key",
+            opts:   Options{CAFile: caFile, CertFile: certFile, KeyFile: notPEM},
+            errMsg: "failed to load client certificate",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            opts := tt.opts
+            opts.BaseURL = server.URL
+            client, err := NewClient(opts)
+            if err == nil {
+                _, err = client.Myself()
+            }
+            if tt.errMsg == "" {
+                if err != nil {
+                    t.Fatalf("Myself: %v", err)
+                }
+                return
+            }
+            if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+                t.Errorf("error = %v, want it to mention %q", err, tt.errMsg)
+            }
+        })
+    }
+}
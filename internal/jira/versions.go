@@ -0,0 +1,87 @@
+package jira
+
+import (
+    "net/http"
+    "net/url"
+    "time"
+)
+
+// ReleaseDateLayout is the date format of version release dates.
+const ReleaseDateLayout = "2006-01-02"
+
+// Version is a project version, used as the fix version of issues.
+type Version struct {
+    ID          string `json:"id,omitempty"`
+    Name        string `json:"name"`
+    Description string `json:"description,omitempty"`
+    Project     string `json:"project,omitempty"`
+    ProjectID   int    `json:"projectId,omitempty"`
+    Released    bool   `json:"released"`
+    ReleaseDate string `json:"releaseDate,omitempty"`
+}
+
+func versionPath(id string) string {
+    return "/rest/api/2/version/" + url.PathEscape(id)
+}
+
+// ProjectVersions lists the versions of a project.
+func (c *Client) ProjectVersions(projectKey string) ([]Version, error) {
+    var versions []Version
+    path := "/rest/api/2/project/" + url.PathEscape(projectKey) + "/versions"
+    if err := c.do(http.MethodGet, path, nil, nil, &versions); err != nil {
+        return nil, err
+    }
+    return versions, nil
+}
+
+// CreateVersion adds an unreleased version to a project.
+func (c *Client) CreateVersion(projectKey, name string) (*Version, error) {
+    body := Version{Name: name, Project: projectKey}
+    var version Version
+    if err := c.do(http.MethodPost, "/rest/api/2/version", nil, body, &version); err != nil {
+        return nil, err
+    }
+    return &version, nil
+}
+
+// EnsureVersion returns the project version called name, creating it when
+// it does not exist yet. created reports whether it was created.
+func (c *Client) EnsureVersion(projectKey, name string) (version *Version, created bool, err error) {
+    versions, err := c.ProjectVersions(projectKey)
+    if err != nil {
+        return nil, false, err
+    }
+    for i := range versions {
+        if versions[i].Name == name {
+            return &versions[i], false, nil
+        }
+    }
+
+    version, err = c.CreateVersion(projectKey, name)
+    if err != nil {
+        return nil, false, err
+    }
+    return version, true, nil
+}
+
+// ReleaseVersion marks a version as released on the given date.
+func (c *Client) ReleaseVersion(id string, date time.Time) error {
+    body := map[string]interface{}{
+        "released":    true,
+        "releaseDate": date.Format(ReleaseDateLayout),
+    }
+    return c.do(http.MethodPut, versionPath(id), nil, body, nil)
+}
+
+// AddFixVersion adds a version to the fix versions of an issue, keeping the
+// ones already set.
+func (c *Client) AddFixVersion(issueKey, versionName string) error {
+    body := map[string]interface{}{
+        "update": map[string]interface{}{
+            "fixVersions": []interface{}{
+                map[string]interface{}{"add": map[string]string{"name": versionName}},
+            },
+        },
+    }
+    return c.do(http.MethodPut, issuePath(issueKey), nil, body, nil)
+}
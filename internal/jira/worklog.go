@@ -0,0 +1,69 @@
+package jira
+
+import (
+    "net/http"
+    "net/url"
+    "strconv"
+    "time"
+)
+
+// TimeLayout is the timestamp format Jira uses for fields such as a
+// worklog's started time.
+const TimeLayout = "2006-01-02T15:04:05.000-0700"
+
+type Worklog struct {
+    ID               string `json:"id,omitempty"`
+    Author           *User  `json:"author,omitempty"`
+    Comment          string `json:"comment,omitempty"`
+    Started          string `json:"started"`
+    TimeSpent        string `json:"timeSpent,omitempty"`
+    TimeSpentSeconds int    `json:"timeSpentSeconds"`
+}
+
+// AddWorklog logs time spent on an issue. Durations are rounded to whole
+// minutes, the smallest unit Jira accepts.
+func (c *Client) AddWorklog(issueKey string, started time.Time, spent time.Duration, comment string) (*Worklog, error) {
+    seconds := int(spent.Round(time.Minute) / time.Second)
+    if seconds < 60 {
+        seconds = 60
+    }
+
+    body := Worklog{
+        Comment:          comment,
+        Started:          started.Format(TimeLayout),
+        TimeSpentSeconds: seconds,
+    }
+    var created Worklog
+    if err := c.do(http.MethodPost, issuePath(issueKey)+"/worklog", nil, body, &created); err != nil {
+        return nil, err
+    }
+    return &created, nil
+}
+
+// StartedTime parses the worklog's started timestamp.
+func (w *Worklog) StartedTime() (time.Time, error) {
+    return time.Parse(TimeLayout, w.Started)
+}
+
+// IssueWorklogs returns every worklog recorded on an issue.
+func (c *Client) IssueWorklogs(issueKey string) ([]Worklog, error) {
+    var worklogs []Worklog
+    for {
+        query := url.Values{
+            "startAt":    {strconv.Itoa(len(worklogs))},
+            "maxResults": {"1000"},
+        }
+        var page struct {
+            Total    int       `json:"total"`
+            Worklogs []Worklog `json:"worklogs"`
+        }
+        if err := c.do(http.MethodGet, issuePath(issueKey)+"/worklog", query, nil, &page); err != nil {
+            return nil, err
+        }
+
+        worklogs = append(worklogs, page.Worklogs...)
+        if len(page.Worklogs) == 0 || len(worklogs) >= page.Total {
+            return worklogs, nil
+        }
+    }
+}
@@ -0,0 +1,97 @@
+package markup
+
+import (
+    "strings"
+
+    "jira-tools/internal/adf"
+)
+
+// MarkdownToADF converts Markdown to an Atlassian Document Format document,
+// the rich text format of version 3 of the REST API.
+func MarkdownToADF(markdown string) *adf.Node {
+    doc := adf.Doc()
+    for _, b := range parseBlocks(markdown) {
+        doc.Content = append(doc.Content, adfBlock(b))
+    }
+    return doc
+}
+
+func adfBlock(b block) *adf.Node {
+    switch b.kind {
+    case headingBlock:
+        return &adf.Node{
+            Type:    adf.TypeHeading,
+            Attrs:   map[string]interface{}{"level": b.level},
+            Content: adfInline(b.text),
+        }
+    case codeBlock:
+        node := &adf.Node{Type: adf.TypeCodeBlock}
+        if b.language != "" {
+            node.Attrs = map[string]interface{}{"language": b.language}
+        }
+        if b.text != "" {
+            node.Content = []*adf.Node{adf.Text(b.text)}
+        }
+        return node
+    case quoteBlock:
+        node := &adf.Node{Type: adf.TypeBlockquote}
+        for _, child := range b.children {
+            node.Content = append(node.Content, adfBlock(child))
+        }
+        return node
+    case ruleBlock:
+        return &adf.Node{Type: adf.TypeRule}
+    case listBlock:
+        node := &adf.Node{Type: adf.TypeBulletList}
+        if b.ordered {
+            node.Type = adf.TypeOrderedList
+        }
+        for _, item := range b.items {
+            listItem := &adf.Node{Type: adf.TypeListItem, Content: []*adf.Node{adf.Paragraph(adfInline(item.text)...)}}
+            for _, child := range item.children {
+                listItem.Content = append(listItem.Content, adfBlock(child))
+            }
+            node.Content = append(node.Content, listItem)
+        }
+        return node
+    default:
+        return adf.Paragraph(adfInline(b.text)...)
+    }
+}
+
+// adfInline converts the lines of a paragraph to text nodes separated by
+// hard breaks.
+func adfInline(text string) []*adf.Node {
+    var nodes []*adf.Node
+    for i, line := range strings.Split(text, "\n") {
+        if i > 0 {
+            nodes = append(nodes, &adf.Node{Type: adf.TypeHardBreak})
+        }
+        for _, s := range parseInline(line, span{}) {
+            nodes = append(nodes, adf.Text(s.text, adfMarks(s)...))
+        }
+    }
+    return nodes
+}
+
+func adfMarks(s span) []adf.Mark {
+    var marks []adf.Mark
+    // The code mark may only be combined with links
+    if s.code {
+        marks = append(marks, adf.Mark{Type: adf.MarkCode})
+    } else {
+        if s.strong {
+            marks = append(marks, adf.Mark{Type: adf.MarkStrong})
+        }
+        if s.em {
+            marks = append(marks, adf.Mark{Type: adf.MarkEm})
+        }
+        if s.strike {
+            marks = append(marks, adf.Mark{Type: adf.MarkStrike})
+        }
+    }
+    if s.href != "" {
+        marks = append(marks, adf.Link(s.href))
+    }
+    return marks
+}
@@ -0,0 +1,251 @@
+package markup
+
+import (
+    "regexp"
+    "strings"
+)
+
+type blockKind int
+
+const (
+    paragraphBlock blockKind = iota
+    headingBlock
+    codeBlock
+    listBlock
+    quoteBlock
+    ruleBlock
+)
+
+// block is a parsed Markdown block. Paragraph and heading text keep their
+// line breaks; inline formatting is parsed when the block is rendered.
+type block struct {
+    kind     blockKind
+    level    int
+    text     string
+    language string
+    ordered  bool
+    items    []listItem
+    children []block
+}
+
+type listItem struct {
+    text string
+    // sublists nested under the item
+    children []block
+}
+
+var (
+    headingLine  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+    fenceLine    = regexp.MustCompile("^\\s*(```|~~~)\\s*([\\w+-]*)")
+    listLine     = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+    ruleLine     = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+    quoteLine    = regexp.MustCompile(`^\s*>\s?(.*)$`)
+    orderedCheck = regexp.MustCompile(`^\d`)
+)
+
+// parseBlocks splits Markdown into blocks. It covers what people write in
+// issue comments: headings, paragraphs, nested lists, fenced code, quotes
+// and rules.
+func parseBlocks(markdown string) []block {
+    lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+    var blocks []block
+
+    for i := 0; i < len(lines); {
+        line := lines[i]
+        switch {
+        case strings.TrimSpace(line) == "":
+            i++
+
+        case fenceLine.MatchString(line):
+            match := fenceLine.FindStringSubmatch(line)
+            var code []string
+            i++
+            for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), match[1]) {
+                code = append(code, lines[i])
+                i++
+            }
+            i++ // closing fence
+            blocks = append(blocks, block{kind: codeBlock, language: match[2], text: strings.Join(code, "\n")})
+
+        case headingLine.MatchString(line):
+            match := headingLine.FindStringSubmatch(line)
+            blocks = append(blocks, block{kind: headingBlock, level: len(match[1]), text: match[2]})
+            i++
+
+        case ruleLine.MatchString(line):
+            blocks = append(blocks, block{kind: ruleBlock})
+            i++
+
+        case quoteLine.MatchString(line):
+            var quoted []string
+            for i < len(lines) && quoteLine.MatchString(lines[i]) {
+                quoted = append(quoted, quoteLine.FindStringSubmatch(lines[i])[1])
+                i++
+            }
+            blocks = append(blocks, block{kind: quoteBlock, children: parseBlocks(strings.Join(quoted, "\n"))})
+
+        case listLine.MatchString(line):
+            var list block
+            list, i = parseList(lines, i)
+            blocks = append(blocks, list)
+
+        default:
+            var text []string
+            for i < len(lines) && strings.TrimSpace(lines[i]) != "" && !startsBlock(lines[i]) {
+                text = append(text, strings.TrimSpace(lines[i]))
+                i++
+            }
+            blocks = append(blocks, block{kind: paragraphBlock, text: strings.Join(text, "\n")})
+        }
+    }
+    return blocks
+}
+
+// startsBlock reports whether a line interrupts a paragraph.
+func startsBlock(line string) bool {
+    return headingLine.MatchString(line) || fenceLine.MatchString(line) ||
+        listLine.MatchString(line) || quoteLine.MatchString(line) || ruleLine.MatchString(line)
+}
+
+// parseList parses the list starting at lines[start] and returns it with
+// the index of the first line after it. Items indented deeper than the
+// first one form sublists.
+func parseList(lines []string, start int) (block, int) {
+    first := listLine.FindStringSubmatch(lines[start])
+    indent := len(first[1])
+    list := block{kind: listBlock, ordered: orderedCheck.MatchString(first[2])}
+
+    i := start
+    for i < len(lines) {
+        line := lines[i]
+        if strings.TrimSpace(line) == "" {
+            // A blank line only continues the list if another item follows
+            next := i + 1
+            for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+                next++
+            }
+            if next < len(lines) {
+                if match := listLine.FindStringSubmatch(lines[next]); match != nil && len(match[1]) >= indent {
+                    i = next
+                    continue
+                }
+            }
+            break
+        }
+
+        match := listLine.FindStringSubmatch(line)
+        lineIndent := len(line) - len(strings.TrimLeft(line, " \t"))
+        switch {
+        case match != nil && len(match[1]) == indent:
+            list.items = append(list.items, listItem{text: match[3]})
+            i++
+        case match != nil && len(match[1]) > indent && len(list.items) > 0:
+            var sublist block
+            sublist, i = parseList(lines, i)
+            last := &list.items[len(list.items)-1]
+            last.children = append(last.children, sublist)
+        case match == nil && lineIndent > indent && len(list.items) > 0:
+            // Continuation of the previous item
+            last := &list.items[len(list.items)-1]
+            last.text += "\n" + strings.TrimSpace(line)
+            i++
+        default:
+            return list, i
+        }
+    }
+    return list, i
+}
+
+// span is a run of text with the same formatting.
+type span struct {
+    text   string
+    strong bool
+    em     bool
+    code   bool
+    strike bool
+    href   string
+}
+
+type inlineKind int
+
+const (
+    plainInline inlineKind = iota
+    codeInline
+    strongInline
+    emInline
+    strikeInline
+    linkInline
+)
+
+// inline is a piece of a line of Markdown: plain text, a code span, or
+// formatting or a link around further pieces.
+type inline struct {
+    kind     inlineKind
+    text     string
+    href     string
+    children []inline
+}
+
+var inlineToken = regexp.MustCompile("`[^`]+`|\\*\\*[^*]+\\*\\*|__[^_]+__|~~[^~]+~~|\\[[^\\]]+\\]\\([^)\\s]+\\)|\\*[^*\\s][^*]*\\*|\\b_[^_\\s][^_]*_\\b")
+
+// parseInlines parses the formatting of a line of Markdown. Underscores
+// inside words, as in snake_case, are not emphasis.
+func parseInlines(text string) []inline {
+    var nodes []inline
+    plain := func(s string) {
+        if s != "" {
+            nodes = append(nodes, inline{kind: plainInline, text: s})
+        }
+    }
+
+    last := 0
+    for _, loc := range inlineToken.FindAllStringIndex(text, -1) {
+        plain(text[last:loc[0]])
+        token := text[loc[0]:loc[1]]
+        switch {
+        case strings.HasPrefix(token, "`"):
+            nodes = append(nodes, inline{kind: codeInline, text: token[1 : len(token)-1]})
+        case strings.HasPrefix(token, "**"), strings.HasPrefix(token, "__"):
+            nodes = append(nodes, inline{kind: strongInline, children: parseInlines(token[2 : len(token)-2])})
+        case strings.HasPrefix(token, "~~"):
+            nodes = append(nodes, inline{kind: strikeInline, children: parseInlines(token[2 : len(token)-2])})
+        case strings.HasPrefix(token, "["):
+            mid := strings.Index(token, "](")
+            nodes = append(nodes, inline{kind: linkInline, href: token[mid+2 : len(token)-1], children: parseInlines(token[1:mid])})
+        default:
+            nodes = append(nodes, inline{kind: emInline, children: parseInlines(token[1 : len(token)-1])})
+        }
+        last = loc[1]
+    }
+    plain(text[last:])
+    return nodes
+}
+
+// parseInline splits a line of Markdown into formatted spans.
+func parseInline(text string, base span) []span {
+    return flattenInlines(parseInlines(text), base)
+}
+
+func flattenInlines(nodes []inline, base span) []span {
+    var spans []span
+    for _, node := range nodes {
+        inner := base
+        switch node.kind {
+        case plainInline, codeInline:
+            inner.text = node.text
+            inner.code = inner.code || node.kind == codeInline
+            spans = append(spans, inner)
+            continue
+        case strongInline:
+            inner.strong = true
+        case emInline:
+            inner.em = true
+        case strikeInline:
+            inner.strike = true
+        case linkInline:
+            inner.href = node.href
+        }
+        spans = append(spans, flattenInlines(node.children, inner)...)
+    }
+    return spans
+}
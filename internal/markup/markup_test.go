@@ -0,0 +1,153 @@
+package markup
+
+import (
+    "encoding/json"
+    "testing"
+)
+
+func TestMarkdownToWiki(t *testing.T) {
+    tests := []struct {
+        name     string
+        markdown string
+        want     string
+    }{
+        {
+            name:     "heading, rule and paragraph",
+            markdown: "# Title\n---\nfirst line\nsecond line",
+            want:     "h1. Title\n\n----\n\nfirst line\nsecond line",
+        },
+        {
+            name:     "nested lists",
+            markdown: "- one\n  - one.a\n    - one.a.i\n- two",
+            want:     "* one\n** one.a\n*** one.a.i\n* two",
+        },
+        {
+            name:     "ordered list with a bullet sublist",
+            markdown: "1. one\n2. two\n   - a\n   - b\n3. three",
+            want:     "# one\n# two\n#* a\n#* b\n# three",
+        },
+        {
+            name:     "blank line between items",
+            markdown: "- one\n\n- two\n\n\nafter",
+            want:     "* one\n* two\n\nafter",
+        },
+        {
+            name:     "fence with a language",
+            markdown: "```go\nfmt.Println(\"*not bold*\")\n```\ntext",
+            want:     "{code:go}\nfmt.Println(\"*not bold*\")\n{code}\n\ntext",
+        },
+        {
+            name:     "unterminated fence",
+            markdown: "```\nfirst\n\nsecond",
+            want:     "{code}\nfirst\n\nsecond\n{code}",
+        },
+        {
+            name:     "emphasis inside a link",
+            markdown: "see [the *new* docs](https://example.com/a_b) now",
+            want:     "see [the _new_ docs|https://example.com/a_b] now",
+        },
+        {
+            name:     "link to itself",
+            markdown: "[https://example.com](https://example.com)",
+            want:     "[https://example.com]",
+        },
+        {
+            name:     "nested formatting",
+            markdown: "**bold _em_** ~~gone~~ `code`",
+            want:     "*bold _em_* -gone- {{code}}",
+        },
+        {
+            name:     "snake_case words",
+            markdown: "rename my_var_name and snake_case_word, keep _em_",
+            want:     "rename my_var_name and snake_case_word, keep _em_",
+        },
+        {
+            name:     "wiki escaping",
+            markdown: "use {braces} [brackets] a|b and `{code}`",
+            want:     "use \\{braces} \\[brackets] a\\|b and {{\\{code\\}}}",
+        },
+        {
+            name:     "braces in code",
+            markdown: "call `map[string]{}` or `}}` and `{noformat}`",
+            want:     "call {{map[string]\\{\\}}} or {{\\}\\}}} and {{\\{noformat\\}}}",
+        },
+        {
+            name:     "quote",
+            markdown: "> quoted\n> **line**",
+            want:     "{quote}\nquoted\n*line*\n{quote}",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := MarkdownToWiki(tt.markdown); got != tt.want {
+                t.Errorf("MarkdownToWiki(%q)\n got %q\nwant %q", tt.markdown, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestMarkdownToADF(t *testing.T) {
+    tests := []struct {
+        name     string
+        markdown string
+        want     string
+    }{
+        {
+            name:     "ordered list with a bullet sublist",
+            markdown: "1. one\n   - a\n2. two",
+            want: `[{"type":"orderedList","content":[` +
+                `{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]},` +
+                `{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]}]}]}]},` +
+                `{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"two"}]}]}]}]`,
+        },
+        {
+            name:     "blank line between items",
+            markdown: "- one\n\n- two",
+            want: `[{"type":"bulletList","content":[` +
+                `{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]}]},` +
+                `{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"two"}]}]}]}]`,
+        },
+        {
+            name:     "fence with a language",
+            markdown: "```go\nx := 1\n```",
+            want:     `[{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"x := 1"}]}]`,
+        },
+        {
+            name:     "unterminated fence",
+            markdown: "```\nx := 1",
+            want:     `[{"type":"codeBlock","content":[{"type":"text","text":"x := 1"}]}]`,
+        },
+        {
+            name:     "emphasis inside a link",
+            markdown: "[the *new* docs](https://example.com)",
+            want: `[{"type":"paragraph","content":[` +
+                `{"type":"text","text":"the ","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},` +
+                `{"type":"text","text":"new","marks":[{"type":"em"},{"type":"link","attrs":{"href":"https://example.com"}}]},` +
+                `{"type":"text","text":" docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]}]}]`,
+        },
+        {
+            name:     "snake_case words and line breaks",
+            markdown: "my_var_name\n_em_",
+            want: `[{"type":"paragraph","content":[{"type":"text","text":"my_var_name"},{"type":"hardBreak"},` +
+                `{"type":"text","text":"em","marks":[{"type":"em"}]}]}]`,
+        },
+        {
+            name:     "code keeps only the link mark",
+            markdown: "[**`run`**](https://example.com)",
+            want: `[{"type":"paragraph","content":[` +
+                `{"type":"text","text":"run","marks":[{"type":"code"},{"type":"link","attrs":{"href":"https://example.com"}}]}]}]`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            doc := MarkdownToADF(tt.markdown)
+            got, err := json.Marshal(doc.Content)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if string(got) != tt.want {
+                t.Errorf("MarkdownToADF(%q)\n got %s\nwant %s", tt.markdown, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,94 @@
+package markup
+
+import (
+    "fmt"
+    "strings"
+)
+
+// wikiEscaper keeps characters that start wiki markup literal.
+var wikiEscaper = strings.NewReplacer("{", "\\{", "[", "\\[", "|", "\\|")
+
+// monospaceEscaper keeps braces inside {{monospace}} literal, so they
+// neither start a macro nor close the span early.
+var monospaceEscaper = strings.NewReplacer("{", "\\{", "}", "\\}")
+
+// MarkdownToWiki converts Markdown to Jira wiki markup, the rich text
+// format of version 2 of the REST API.
+func MarkdownToWiki(markdown string) string {
+    var parts []string
+    for _, b := range parseBlocks(markdown) {
+        parts = append(parts, wikiBlock(b, ""))
+    }
+    return strings.Join(parts, "\n\n")
+}
+
+func wikiBlock(b block, listPrefix string) string {
+    switch b.kind {
+    case headingBlock:
+        return fmt.Sprintf("h%d. %s", b.level, wikiInline(b.text))
+    case codeBlock:
+        if b.language != "" {
+            return fmt.Sprintf("{code:%s}\n%s\n{code}", b.language, b.text)
+        }
+        return "{code}\n" + b.text + "\n{code}"
+    case quoteBlock:
+        var parts []string
+        for _, child := range b.children {
+            parts = append(parts, wikiBlock(child, ""))
+        }
+        return "{quote}\n" + strings.Join(parts, "\n\n") + "\n{quote}"
+    case ruleBlock:
+        return "----"
+    case listBlock:
+        marker := "*"
+        if b.ordered {
+            marker = "#"
+        }
+        prefix := listPrefix + marker
+        var lines []string
+        for _, item := range b.items {
+            lines = append(lines, prefix+" "+wikiInline(item.text))
+            for _, child := range item.children {
+                lines = append(lines, wikiBlock(child, prefix))
+            }
+        }
+        return strings.Join(lines, "\n")
+    default:
+        return wikiInline(b.text)
+    }
+}
+
+func wikiInline(text string) string {
+    var lines []string
+    for _, line := range strings.Split(text, "\n") {
+        lines = append(lines, wikiInlines(parseInlines(line)))
+    }
+    return strings.Join(lines, "\n")
+}
+
+// wikiInlines renders formatting nested the way it was written, so a link
+// with emphasis in its label stays one link.
+func wikiInlines(nodes []inline) string {
+    var b strings.Builder
+    for _, node := range nodes {
+        switch node.kind {
+        case codeInline:
+            b.WriteString("{{" + monospaceEscaper.Replace(node.text) + "}}")
+        case strongInline:
+            b.WriteString("*" + wikiInlines(node.children) + "*")
+        case emInline:
+            b.WriteString("_" + wikiInlines(node.children) + "_")
+        case strikeInline:
+            b.WriteString("-" + wikiInlines(node.children) + "-")
+        case linkInline:
+            if len(node.children) == 1 && node.children[0].kind == plainInline && node.children[0].text == node.href {
+                b.WriteString("[" + node.href + "]")
+            } else {
+                b.WriteString("[" + wikiInlines(node.children) + "|" + node.href + "]")
+            }
+        default:
+            b.WriteString(wikiEscaper.Replace(node.text))
+        }
+    }
+    return b.String()
+}
@@ -0,0 +1,88 @@
+package timetrack
+
+import (
+    "bufio"
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "sort"
+    "time"
+)
+
+const activityFile = "activity.log"
+
+// Kinds of recorded git activity.
+const (
+    EventCheckout = "checkout"
+    EventCommit   = "commit"
+)
+
+// Event is a piece of git activity recorded by the jt git hooks.
+type Event struct {
+    Time     time.Time `json:"time"`
+    Kind     string    `json:"kind"`
+    Repo     string    `json:"repo"`
+    Branch   string    `json:"branch"`
+    IssueKey string    `json:"issue_key,omitempty"`
+}
+
+// RecordEvent appends an event to the activity log.
+func (s *Store) RecordEvent(event Event) error {
+    data, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+
+    f, err := os.OpenFile(filepath.Join(s.dir, activityFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    _, err = f.Write(append(data, '\n'))
+    return err
+}
+
+// Events returns the recorded events in [from, to), oldest first, plus the
+// last event before from so the activity in progress at from is known.
+func (s *Store) Events(from, to time.Time) ([]Event, error) {
+    f, err := os.Open(filepath.Join(s.dir, activityFile))
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    var all []Event
+    scanner := bufio.NewScanner(f)
+    for scanner.Scan() {
+        var event Event
+        if json.Unmarshal(scanner.Bytes(), &event) != nil {
+            // Skip lines torn by concurrent writers.
+            continue
+        }
+        all = append(all, event)
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+
+    sort.SliceStable(all, func(i, j int) bool { return all[i].Time.Before(all[j].Time) })
+
+    var events []Event
+    var previous *Event
+    for i := range all {
+        switch {
+        case all[i].Time.Before(from):
+            previous = &all[i]
+        case all[i].Time.Before(to):
+            events = append(events, all[i])
+        }
+    }
+    if previous != nil {
+        events = append([]Event{*previous}, events...)
+    }
+    return events, nil
+}
@@ -0,0 +1,75 @@
+package timetrack
+
+import (
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Jira's default time tracking settings: a working day is 8 hours and a
+// working week is 5 days.
+const (
+    HoursPerDay = 8
+    DaysPerWeek = 5
+)
+
+var (
+    durationPattern = regexp.MustCompile(`^(?:\s*\d+(?:\.\d+)?\s*[wdhm]\s*)+$`)
+    durationPart    = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*([wdhm])`)
+)
+
+// ParseDuration parses Jira duration syntax such as "1w 2d 3h 4m", "1h30m"
+// or "2.5h". Weeks and days are working weeks and days.
+func ParseDuration(value string) (time.Duration, error) {
+    value = strings.ToLower(strings.TrimSpace(value))
+    if value == "" || !durationPattern.MatchString(value) {
+        return 0, fmt.Errorf("invalid duration %q (expected e.g. 1w 2d 3h 4m)", value)
+    }
+
+    var total time.Duration
+    for _, match := range durationPart.FindAllStringSubmatch(value, -1) {
+        amount, err := strconv.ParseFloat(match[1], 64)
+        if err != nil {
+            return 0, fmt.Errorf("invalid duration %q: %v", value, err)
+        }
+        total += time.Duration(amount * float64(unitDuration(match[2])))
+    }
+
+    if total < time.Minute {
+        return 0, fmt.Errorf("duration %q is shorter than one minute", value)
+    }
+    return total.Round(time.Minute), nil
+}
+
+// FormatDuration renders a duration in Jira syntax, e.g. "1d 2h 30m".
+func FormatDuration(d time.Duration) string {
+    d = d.Round(time.Minute)
+    if d <= 0 {
+        return "0m"
+    }
+
+    var parts []string
+    for _, unit := range []string{"w", "d", "h", "m"} {
+        size := unitDuration(unit)
+        if n := d / size; n > 0 {
+            parts = append(parts, fmt.Sprintf("%d%s", n, unit))
+            d -= n * size
+        }
+    }
+    return strings.Join(parts, " ")
+}
+
+func unitDuration(unit string) time.Duration {
+    switch unit {
+    case "w":
+        return DaysPerWeek * HoursPerDay * time.Hour
+    case "d":
+        return HoursPerDay * time.Hour
+    case "h":
+        return time.Hour
+    default:
+        return time.Minute
+    }
+}
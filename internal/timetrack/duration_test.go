@@ -0,0 +1,75 @@
+package timetrack
+
+import (
+    "testing"
+    "time"
+)
+
+func TestParseDuration(t *testing.T) {
+    tests := []struct {
+        in      string
+        want    time.Duration
+        wantErr bool
+    }{
+        {in: "1w 2d 3h 4m", want: (5*8+2*8+3)*time.Hour + 4*time.Minute},
+        {in: "90m", want: 90 * time.Minute},
+        {in: "1.5h", want: 90 * time.Minute},
+        {in: "1h30m", want: 90 * time.Minute},
+        {in: " 2D 1H ", want: 17 * time.Hour},
+        {in: "0.5d", want: 4 * time.Hour},
+        {in: "", wantErr: true},
+        {in: "   ", wantErr: true},
+        {in: "abc", wantErr: true},
+        {in: "3", wantErr: true},
+        {in: "2x", wantErr: true},
+        {in: "1h and 5m", wantErr: true},
+        {in: "0m", wantErr: true},
+        {in: "10s", wantErr: true},
+    }
+    for _, tt := range tests {
+        got, err := ParseDuration(tt.in)
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("ParseDuration(%q) = %v, want an error", tt.in, got)
+            }
+            continue
+        }
+        if err != nil || got != tt.want {
+            t.Errorf("ParseDuration(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+        }
+    }
+}
+
+func TestFormatDuration(t *testing.T) {
+    tests := []struct {
+        in   time.Duration
+        want string
+    }{
+        {0, "0m"},
+        {-time.Hour, "0m"},
+        {90 * time.Minute, "1h 30m"},
+        {8 * time.Hour, "1d"},
+        {(5*8+2*8+3)*time.Hour + 4*time.Minute, "1w 2d 3h 4m"},
+        {29 * time.Second, "0m"},
+        {90 * time.Second, "2m"},
+    }
+    for _, tt := range tests {
+        if got := FormatDuration(tt.in); got != tt.want {
+            t.Errorf("FormatDuration(%v) = %q, want %q", tt.in, got, tt.want)
+        }
+    }
+}
+
+func TestDurationRoundTrip(t *testing.T) {
+    for _, in := range []string{"1w 2d 3h 4m", "90m", "1.5h", "3d", "45m", "2w"} {
+        parsed, err := ParseDuration(in)
+        if err != nil {
+            t.Fatalf("ParseDuration(%q): %v", in, err)
+        }
+        formatted := FormatDuration(parsed)
+        again, err := ParseDuration(formatted)
+        if err != nil || again != parsed {
+            t.Errorf("%q formats as %q, which parses as %v, %v, want %v", in, formatted, again, err, parsed)
+        }
+    }
+}
@@ -0,0 +1,290 @@
+package timetrack
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "sort"
+    "strconv"
+    "strings"
+    "text/tabwriter"
+    "time"
+
+    "jira-tools/internal/jira"
+)
+
+// Report groupings for the timesheet grid.
+const (
+    GroupByIssue   = "issue"
+    GroupByProject = "project"
+)
+
+// Entry is one block of logged or locally tracked time.
+type Entry struct {
+    IssueKey string        `json:"issue_key"`
+    Project  string        `json:"project"`
+    Summary  string        `json:"summary,omitempty"`
+    Author   string        `json:"author,omitempty"`
+    Started  time.Time     `json:"started"`
+    Duration time.Duration `json:"-"`
+    Comment  string        `json:"comment,omitempty"`
+    // Synced is false for time that only exists locally: the running timer
+    // and worklogs waiting for `jt time sync`.
+    Synced bool `json:"synced"`
+}
+
+// Report is the time logged between From (inclusive) and To (exclusive).
+type Report struct {
+    From    time.Time
+    To      time.Time
+    Entries []Entry
+}
+
+// Add appends an entry if it started inside the report period.
+func (r *Report) Add(entry Entry) {
+    if entry.Started.Before(r.From) || !entry.Started.Before(r.To) {
+        return
+    }
+    if entry.Project == "" {
+        entry.Project = jira.ProjectKey(entry.IssueKey)
+    }
+    r.Entries = append(r.Entries, entry)
+}
+
+// Sort orders entries by start time, then issue key.
+func (r *Report) Sort() {
+    sort.SliceStable(r.Entries, func(i, j int) bool {
+        if !r.Entries[i].Started.Equal(r.Entries[j].Started) {
+            return r.Entries[i].Started.Before(r.Entries[j].Started)
+        }
+        return r.Entries[i].IssueKey < r.Entries[j].IssueKey
+    })
+}
+
+// Days returns every day of the report period.
+func (r *Report) Days() []time.Time {
+    var days []time.Time
+    for day := startOfDay(r.From); day.Before(r.To); day = day.AddDate(0, 0, 1) {
+        days = append(days, day)
+    }
+    return days
+}
+
+// Total returns the sum of all entries.
+func (r *Report) Total() time.Duration {
+    var total time.Duration
+    for _, entry := range r.Entries {
+        total += entry.Duration
+    }
+    return total
+}
+
+// Totals sums entries by row (issue or project) and by day.
+func (r *Report) Totals(groupBy string) map[string]map[string]time.Duration {
+    totals := make(map[string]map[string]time.Duration)
+    for _, entry := range r.Entries {
+        row := entry.IssueKey
+        if groupBy == GroupByProject {
+            row = entry.Project
+        }
+        if totals[row] == nil {
+            totals[row] = make(map[string]time.Duration)
+        }
+        totals[row][dayKey(entry.Started)] += entry.Duration
+    }
+    return totals
+}
+
+// WriteGrid renders a timesheet with one row per issue or project and one
+// column per day, in decimal hours.
+func (r *Report) WriteGrid(w io.Writer, groupBy string) error {
+    days := r.Days()
+    totals := r.Totals(groupBy)
+
+    rows := make([]string, 0, len(totals))
+    for row := range totals {
+        rows = append(rows, row)
+    }
+    sort.Strings(rows)
+
+    summaries := make(map[string]string)
+    for _, entry := range r.Entries {
+        if groupBy != GroupByProject && entry.Summary != "" {
+            summaries[entry.IssueKey] = entry.Summary
+        }
+    }
+
+    tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+    header := []string{strings.ToUpper(groupBy)}
+    for _, day := range days {
+        header = append(header, day.Format("Mon 01-02"))
+    }
+    header = append(header, "TOTAL", "")
+    fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+    dayTotals := make(map[string]time.Duration)
+    for _, row := range rows {
+        cells := []string{row}
+        var rowTotal time.Duration
+        for _, day := range days {
+            d := totals[row][dayKey(day)]
+            dayTotals[dayKey(day)] += d
+            rowTotal += d
+            cells = append(cells, FormatHours(d))
+        }
+        cells = append(cells, FormatHours(rowTotal), truncate(summaries[row], 40))
+        fmt.Fprintln(tw, strings.Join(cells, "\t"))
+    }
+
+    footer := []string{"TOTAL"}
+    for _, day := range days {
+        footer = append(footer, FormatHours(dayTotals[dayKey(day)]))
+    }
+    footer = append(footer, FormatHours(r.Total()), "")
+    fmt.Fprintln(tw, strings.Join(footer, "\t"))
+
+    return tw.Flush()
+}
+
+// WriteCSV writes one row per entry.
+func (r *Report) WriteCSV(w io.Writer) error {
+    cw := csv.NewWriter(w)
+    if err := cw.Write([]string{"date", "issue", "project", "summary", "started", "hours", "duration", "comment", "synced"}); err != nil {
+        return err
+    }
+    for _, entry := range r.Entries {
+        record := []string{
+            dayKey(entry.Started),
+            entry.IssueKey,
+            entry.Project,
+            entry.Summary,
+            entry.Started.Format(time.RFC3339),
+            FormatHours(entry.Duration),
+            FormatDuration(entry.Duration),
+            entry.Comment,
+            strconv.FormatBool(entry.Synced),
+        }
+        if err := cw.Write(record); err != nil {
+            return err
+        }
+    }
+    cw.Flush()
+    return cw.Error()
+}
+
+// WriteJSON writes the entries together with per day, issue and project totals.
+func (r *Report) WriteJSON(w io.Writer) error {
+    type jsonEntry struct {
+        Entry
+        Seconds int64 `json:"seconds"`
+    }
+    out := struct {
+        From      string             `json:"from"`
+        To        string             `json:"to"`
+        Entries   []jsonEntry        `json:"entries"`
+        ByDay     map[string]float64 `json:"hours_by_day"`
+        ByIssue   map[string]float64 `json:"hours_by_issue"`
+        ByProject map[string]float64 `json:"hours_by_project"`
+        Total     float64            `json:"total_hours"`
+    }{
+        From:      dayKey(r.From),
+        To:        dayKey(r.To.Add(-time.Nanosecond)),
+        Entries:   []jsonEntry{},
+        ByDay:     make(map[string]float64),
+        ByIssue:   make(map[string]float64),
+        ByProject: make(map[string]float64),
+        Total:     r.Total().Hours(),
+    }
+    for _, entry := range r.Entries {
+        out.Entries = append(out.Entries, jsonEntry{entry, int64(entry.Duration / time.Second)})
+        out.ByDay[dayKey(entry.Started)] += entry.Duration.Hours()
+        out.ByIssue[entry.IssueKey] += entry.Duration.Hours()
+        out.ByProject[entry.Project] += entry.Duration.Hours()
+    }
+
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    return enc.Encode(out)
+}
+
+// WriteICS writes every entry as an iCalendar event.
+func (r *Report) WriteICS(w io.Writer) error {
+    const stamp = "20060102T150405Z"
+    now := time.Now().UTC().Format(stamp)
+
+    lines := []string{
+        "BEGIN:VCALENDAR",
+        "VERSION:2.0",
+        "PRODID:-//jira-tools//jt time report//EN",
+        "CALSCALE:GREGORIAN",
+    }
+    for _, entry := range r.Entries {
+        summary := entry.IssueKey
+        if entry.Summary != "" {
+            summary += ": " + entry.Summary
+        }
+        start := entry.Started.UTC()
+        lines = append(lines,
+            "BEGIN:VEVENT",
+            fmt.Sprintf("UID:%s-%s@jira-tools", entry.IssueKey, start.Format(stamp)),
+            "DTSTAMP:"+now,
+            "DTSTART:"+start.Format(stamp),
+            "DTEND:"+start.Add(entry.Duration).Format(stamp),
+            "SUMMARY:"+icsEscape(summary),
+        )
+        if entry.Comment != "" {
+            lines = append(lines, "DESCRIPTION:"+icsEscape(entry.Comment))
+        }
+        lines = append(lines, "END:VEVENT")
+    }
+    lines = append(lines, "END:VCALENDAR")
+
+    for _, line := range lines {
+        if _, err := io.WriteString(w, foldICSLine(line)+"\r\n"); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// FormatHours renders a duration as decimal hours, e.g. 1.5, or "-" for zero.
+func FormatHours(d time.Duration) string {
+    if d == 0 {
+        return "-"
+    }
+    return strconv.FormatFloat(d.Hours(), 'f', 2, 64)
+}
+
+func dayKey(t time.Time) string {
+    return t.Format("2006-01-02")
+}
+
+func truncate(s string, max int) string {
+    runes := []rune(s)
+    if len(runes) <= max {
+        return s
+    }
+    return string(runes[:max-1]) + "…"
+}
+
+func icsEscape(s string) string {
+    replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+    return replacer.Replace(s)
+}
+
+// foldICSLine wraps lines longer than 75 octets as required by RFC 5545.
+func foldICSLine(line string) string {
+    var b strings.Builder
+    width := 0
+    for _, r := range line {
+        size := len(string(r))
+        if width+size > 75 {
+            b.WriteString("\r\n ")
+            width = 1
+        }
+        b.WriteRune(r)
+        width += size
+    }
+    return b.String()
+}
@@ -0,0 +1,170 @@
+package timetrack
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "time"
+)
+
+const (
+    stateFile = "timers.json"
+    lockFile  = "timers.lock"
+
+    // staleLockAge is how long a lock file may exist before it is assumed
+    // to be left over from a crashed process.
+    staleLockAge = 30 * time.Second
+)
+
+// Timer is a running timer on an issue.
+type Timer struct {
+    IssueKey string    `json:"issue_key"`
+    Started  time.Time `json:"started"`
+    Comment  string    `json:"comment,omitempty"`
+}
+
+// Worklog is a stopped timer that has not been posted to Jira yet.
+type Worklog struct {
+    IssueKey string        `json:"issue_key"`
+    Started  time.Time     `json:"started"`
+    Duration time.Duration `json:"duration"`
+    Comment  string        `json:"comment,omitempty"`
+}
+
+// State is everything the tracker persists between runs.
+type State struct {
+    Active  *Timer    `json:"active,omitempty"`
+    Pending []Worklog `json:"pending,omitempty"`
+    // Logged holds the time ranges of suggestions already logged to Jira,
+    // so later suggestions only cover the work done since.
+    Logged []Interval `json:"logged,omitempty"`
+}
+
+// Store persists timer state as JSON in a directory, normally
+// ~/.jira-tools, so timers survive process exits and reboots.
+type Store struct {
+    dir string
+}
+
+func NewStore(dir string) *Store {
+    return &Store{dir: dir}
+}
+
+// Load reads the current state. A missing state file is an empty state.
+func (s *Store) Load() (*State, error) {
+    data, err := os.ReadFile(filepath.Join(s.dir, stateFile))
+    if os.IsNotExist(err) {
+        return &State{}, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    var state State
+    if err := json.Unmarshal(data, &state); err != nil {
+        return nil, fmt.Errorf("corrupt timer state %s: %v", filepath.Join(s.dir, stateFile), err)
+    }
+    return &state, nil
+}
+
+// Update loads the state, applies fn and saves the result while holding a
+// lock, so concurrent jt processes cannot both start a timer.
+func (s *Store) Update(fn func(state *State) error) error {
+    unlock, err := s.lock()
+    if err != nil {
+        return err
+    }
+    defer unlock()
+
+    state, err := s.Load()
+    if err != nil {
+        return err
+    }
+    if err := fn(state); err != nil {
+        return err
+    }
+    return s.save(state)
+}
+
+// Start begins a timer on an issue. It refuses to run two timers at once.
+func (s *Store) Start(issueKey, comment string, now time.Time) (*Timer, error) {
+    var timer *Timer
+    err := s.Update(func(state *State) error {
+        if state.Active != nil {
+            return fmt.Errorf("timer already running for %s since %s, stop it first",
+                state.Active.IssueKey, state.Active.Started.Format("2006-01-02 15:04"))
+        }
+        timer = &Timer{IssueKey: issueKey, Started: now, Comment: comment}
+        state.Active = timer
+        return nil
+    })
+    return timer, err
+}
+
+// Stop ends the running timer and returns it as a worklog. The worklog is
+// not queued; callers add it to Pending if posting it to Jira fails.
+func (s *Store) Stop(now time.Time) (*Worklog, error) {
+    var worklog *Worklog
+    err := s.Update(func(state *State) error {
+        if state.Active == nil {
+            return fmt.Errorf("no timer is running")
+        }
+        worklog = &Worklog{
+            IssueKey: state.Active.IssueKey,
+            Started:  state.Active.Started,
+            Duration: now.Sub(state.Active.Started),
+            Comment:  state.Active.Comment,
+        }
+        state.Active = nil
+        return nil
+    })
+    return worklog, err
+}
+
+// AddPending queues a worklog for a later sync.
+func (s *Store) AddPending(worklog Worklog) error {
+    return s.Update(func(state *State) error {
+        state.Pending = append(state.Pending, worklog)
+        return nil
+    })
+}
+
+func (s *Store) save(state *State) error {
+    data, err := json.MarshalIndent(state, "", "  ")
+    if err != nil {
+        return err
+    }
+
+    // Write to a temporary file first so a crash never leaves a truncated state.
+    tmp := filepath.Join(s.dir, stateFile+".tmp")
+    if err := os.WriteFile(tmp, data, 0600); err != nil {
+        return err
+    }
+    return os.Rename(tmp, filepath.Join(s.dir, stateFile))
+}
+
+func (s *Store) lock() (func(), error) {
+    path := filepath.Join(s.dir, lockFile)
+    deadline := time.Now().Add(5 * time.Second)
+
+    for {
+        f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+        if err == nil {
+            f.Close()
+            return func() { os.Remove(path) }, nil
+        }
+        if !os.IsExist(err) {
+            return nil, fmt.Errorf("failed to lock timer state: %v", err)
+        }
+
+        if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+            os.Remove(path)
+            continue
+        }
+        if time.Now().After(deadline) {
+            return nil, fmt.Errorf("timer state is locked by another jt process (%s)", path)
+        }
+        time.Sleep(50 * time.Millisecond)
+    }
+}
@@ -0,0 +1,172 @@
+package timetrack
+
+import (
+    "sort"
+    "time"
+)
+
+// SuggestOptions controls how recorded activity is turned into worklogs.
+type SuggestOptions struct {
+    From time.Time
+    To   time.Time
+    // WorkStart and WorkEnd are offsets from midnight bounding the working
+    // day; time outside them is never suggested.
+    WorkStart time.Duration
+    WorkEnd   time.Duration
+    // IdleGap is the longest stretch without recorded activity that still
+    // counts as work. Longer stretches are capped and reported as idle.
+    IdleGap time.Duration
+    // MinDuration drops suggestions shorter than this.
+    MinDuration time.Duration
+    // Logged are ranges already logged to Jira. Work on the same issue
+    // inside them is not suggested again.
+    Logged []Interval
+}
+
+// Suggestion is a proposed worklog for one issue on one day. Started and
+// Ended bound the work it covers.
+type Suggestion struct {
+    Day      time.Time
+    IssueKey string
+    Started  time.Time
+    Ended    time.Time
+    Duration time.Duration
+}
+
+// Range returns the time range the suggestion covers, to record it as
+// logged once submitted.
+func (s Suggestion) Range() Interval {
+    return Interval{IssueKey: s.IssueKey, Start: s.Started, End: s.Ended}
+}
+
+// Interval is a stretch of time spent on an issue.
+type Interval struct {
+    IssueKey string    `json:"issue_key"`
+    Start    time.Time `json:"start"`
+    End      time.Time `json:"end"`
+}
+
+// Suggest infers the time spent per issue per day from checkout and commit
+// events. Each event marks the start of work on its branch's issue until
+// the next event. It returns the suggestions and the idle intervals that
+// were split out because no activity was recorded for longer than IdleGap.
+func Suggest(events []Event, opts SuggestOptions) ([]Suggestion, []Interval) {
+    var work, idle []Interval
+    for i, event := range events {
+        end := opts.To
+        if i+1 < len(events) {
+            end = events[i+1].Time
+        }
+        start := event.Time
+        if start.Before(opts.From) {
+            start = opts.From
+        }
+        if end.After(opts.To) {
+            end = opts.To
+        }
+        if !end.After(start) || event.IssueKey == "" {
+            continue
+        }
+
+        if opts.IdleGap > 0 && end.Sub(event.Time) > opts.IdleGap {
+            idleStart := event.Time.Add(opts.IdleGap)
+            if idleStart.Before(start) {
+                idleStart = start
+            }
+            idle = append(idle, clipWorkingHours(Interval{event.IssueKey, idleStart, end}, opts)...)
+            end = idleStart
+        }
+        for _, part := range clipWorkingHours(Interval{event.IssueKey, start, end}, opts) {
+            work = append(work, withoutLogged(part, opts.Logged)...)
+        }
+    }
+
+    type dayIssue struct {
+        day   string
+        issue string
+    }
+    totals := make(map[dayIssue]*Suggestion)
+    for _, interval := range work {
+        day := startOfDay(interval.Start)
+        key := dayIssue{day.Format("2006-01-02"), interval.IssueKey}
+        suggestion, ok := totals[key]
+        if !ok {
+            suggestion = &Suggestion{Day: day, IssueKey: interval.IssueKey, Started: interval.Start}
+            totals[key] = suggestion
+        }
+        if interval.Start.Before(suggestion.Started) {
+            suggestion.Started = interval.Start
+        }
+        if interval.End.After(suggestion.Ended) {
+            suggestion.Ended = interval.End
+        }
+        suggestion.Duration += interval.End.Sub(interval.Start)
+    }
+
+    var suggestions []Suggestion
+    for _, suggestion := range totals {
+        suggestion.Duration = suggestion.Duration.Round(time.Minute)
+        if suggestion.Duration < opts.MinDuration || suggestion.Duration < time.Minute {
+            continue
+        }
+        suggestions = append(suggestions, *suggestion)
+    }
+    sort.Slice(suggestions, func(i, j int) bool {
+        if !suggestions[i].Day.Equal(suggestions[j].Day) {
+            return suggestions[i].Day.Before(suggestions[j].Day)
+        }
+        return suggestions[i].IssueKey < suggestions[j].IssueKey
+    })
+
+    return suggestions, idle
+}
+
+// clipWorkingHours splits an interval per day and keeps only the parts
+// inside the working hours.
+func clipWorkingHours(interval Interval, opts SuggestOptions) []Interval {
+    var parts []Interval
+    for day := startOfDay(interval.Start); day.Before(interval.End); day = day.AddDate(0, 0, 1) {
+        start, end := day.Add(opts.WorkStart), day.Add(opts.WorkEnd)
+        if interval.Start.After(start) {
+            start = interval.Start
+        }
+        if interval.End.Before(end) {
+            end = interval.End
+        }
+        if end.After(start) {
+            parts = append(parts, Interval{interval.IssueKey, start, end})
+        }
+    }
+    return parts
+}
+
+// withoutLogged removes the parts of an interval that fall in logged ranges
+// of the same issue.
+func withoutLogged(interval Interval, logged []Interval) []Interval {
+    parts := []Interval{interval}
+    for _, done := range logged {
+        if done.IssueKey != interval.IssueKey {
+            continue
+        }
+        var remaining []Interval
+        for _, part := range parts {
+            if !done.Start.Before(part.End) || !done.End.After(part.Start) {
+                remaining = append(remaining, part)
+                continue
+            }
+            if part.Start.Before(done.Start) {
+                remaining = append(remaining, Interval{part.IssueKey, part.Start, done.Start})
+            }
+            if part.End.After(done.End) {
+                remaining = append(remaining, Interval{part.IssueKey, done.End, part.End})
+            }
+        }
+        parts = remaining
+    }
+    return parts
+}
+
+func startOfDay(t time.Time) time.Time {
+    year, month, day := t.Date()
+    return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
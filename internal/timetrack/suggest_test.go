@@ -0,0 +1,96 @@
+package timetrack
+
+import (
+    "testing"
+    "time"
+)
+
+func at(clock string) time.Time {
+    t, err := time.ParseInLocation("2006-01-02 15:04", "2026-03-02 "+clock, time.Local)
+    if err != nil {
+        panic(err)
+    }
+    return t
+}
+
+func TestSuggestSkipsLoggedRanges(t *testing.T) {
+    events := []Event{
+        {Time: at("09:00"), Kind: EventCheckout, IssueKey: "PROJ-1"},
+        {Time: at("11:00"), Kind: EventCheckout, IssueKey: "PROJ-2"},
+        {Time: at("12:00"), Kind: EventCommit, IssueKey: "PROJ-2"},
+        {Time: at("13:00"), Kind: EventCheckout, IssueKey: "PROJ-1"},
+    }
+    opts := SuggestOptions{
+        From:      at("00:00"),
+        To:        at("16:00"),
+        WorkStart: 9 * time.Hour,
+        WorkEnd:   17 * time.Hour,
+    }
+
+    // A suggestion submitted at 12:00 logged the morning on both issues
+    morning := opts
+    morning.To = at("12:00")
+    submitted, _ := Suggest(events, morning)
+    for _, s := range submitted {
+        opts.Logged = append(opts.Logged, s.Range())
+    }
+
+    tests := []struct {
+        name   string
+        logged []Interval
+        want   map[string]time.Duration
+    }{
+        {
+            name: "nothing logged",
+            want: map[string]time.Duration{"PROJ-1": 5 * time.Hour, "PROJ-2": 2 * time.Hour},
+        },
+        {
+            name:   "morning logged",
+            logged: opts.Logged,
+            want:   map[string]time.Duration{"PROJ-1": 3 * time.Hour, "PROJ-2": time.Hour},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            run := opts
+            run.Logged = tt.logged
+            suggestions, _ := Suggest(events, run)
+            got := make(map[string]time.Duration)
+            for _, s := range suggestions {
+                got[s.IssueKey] = s.Duration
+            }
+            if len(got) != len(tt.want) {
+                t.Fatalf("got %v, want %v", got, tt.want)
+            }
+            for key, duration := range tt.want {
+                if got[key] != duration {
+                    t.Errorf("%s: got %v, want %v", key, got[key], duration)
+                }
+            }
+        })
+    }
+}
+
+func TestSuggestionRange(t *testing.T) {
+    events := []Event{
+        {Time: at("08:00"), Kind: EventCheckout, IssueKey: "PROJ-1"},
+        {Time: at("10:00"), Kind: EventCheckout, IssueKey: "PROJ-2"},
+        {Time: at("11:00"), Kind: EventCheckout, IssueKey: "PROJ-1"},
+    }
+    suggestions, _ := Suggest(events, SuggestOptions{
+        From:      at("00:00"),
+        To:        at("12:30"),
+        WorkStart: 9 * time.Hour,
+        WorkEnd:   17 * time.Hour,
+    })
+    if len(suggestions) != 2 || suggestions[0].IssueKey != "PROJ-1" {
+        t.Fatalf("got %+v, want suggestions for PROJ-1 and PROJ-2", suggestions)
+    }
+    r := suggestions[0].Range()
+    if !r.Start.Equal(at("09:00")) || !r.End.Equal(at("12:30")) {
+        t.Errorf("PROJ-1 range = %s-%s, want 09:00-12:30", r.Start.Format("15:04"), r.End.Format("15:04"))
+    }
+    if suggestions[0].Duration != 2*time.Hour+30*time.Minute {
+        t.Errorf("PROJ-1 duration = %v, want 2h30m", suggestions[0].Duration)
+    }
+}